@@ -30,15 +30,15 @@ The most commonly used git commands are:
 
 See 'git help <command>' for more information on a specific command.
 `
-	args, _ := docopt.Parse(usage, nil, true, "git version 1.7.4.4", true)
+	parser := &docopt.Parser{OptionsFirst: true}
+	args, _ := parser.ParseArgs(usage, nil, "git version 1.7.4.4")
 
-	fmt.Println("global arguments:")
-	fmt.Println(args)
-
-	fmt.Println("command arguments:")
 	cmd := args["<command>"].(string)
 	cmdArgs := args["<args>"].([]string)
 
+	fmt.Println("global arguments:", args)
+	fmt.Println("command arguments:", cmd, cmdArgs)
+
 	err := runCommand(cmd, cmdArgs)
 	if err != nil {
 		fmt.Println(err)
@@ -62,23 +62,45 @@ func goRun(scriptName string, args []string) (err error) {
 }
 
 func runCommand(cmd string, args []string) (err error) {
-	argv := make([]string, 1)
-	argv[0] = cmd
-	argv = append(argv, args...)
+	argv := append([]string{cmd}, args...)
 	switch cmd {
 	case "add":
 		// subcommand is a function call
 		return cmdAdd(argv)
 	case "branch":
 		// subcommand is a script
-		return goRun("git_branch.go", argv)
+		return goRun("branch/git_branch.go", argv)
 	case "checkout", "clone", "commit", "push", "remote":
 		// subcommand is a script
-		scriptName := fmt.Sprintf("git_%s.go", cmd)
+		scriptName := fmt.Sprintf("%s/git_%s.go", cmd, cmd)
 		return goRun(scriptName, argv)
 	case "help", "":
-		return goRun("git.go", []string{"git_add.go", "--help"})
+		return goRun("git.go", append(argv[1:], "--help"))
 	}
 
 	return fmt.Errorf("%s is not a git command. See 'git help'", cmd)
 }
+
+func cmdAdd(argv []string) (err error) {
+	usage := `usage: git add [options] [--] [<filepattern>...]
+
+options:
+	-h, --help
+	-n, --dry-run        dry run
+	-v, --verbose        be verbose
+	-i, --interactive    interactive picking
+	-p, --patch          select hunks interactively
+	-e, --edit           edit current diff and apply
+	-f, --force          allow adding otherwise ignored files
+	-u, --update         update tracked files
+	-N, --intent-to-add  record only the fact that the path will be added later
+	-A, --all            add all, noticing removal of tracked files
+	--refresh            don't add, only refresh the index
+	--ignore-errors      just skip files which cannot be added because of errors
+	--ignore-missing     check if - even missing - files are ignored in dry run
+`
+
+	args, _ := docopt.ParseDoc(usage)
+	fmt.Println(args)
+	return
+}
@@ -6,9 +6,9 @@ import (
 )
 
 func main() {
-	usage := `usage: foo [-x] [-y]`
+	usage := `usage: type_assert [-x] [-y]`
 
-	arguments, err := docopt.Parse(usage, nil, true, "", false)
+	arguments, err := docopt.ParseDoc(usage)
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -0,0 +1,4 @@
+package main
+
+// Dummy main so that the example builds.
+func main() {}
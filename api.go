@@ -0,0 +1,128 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DefaultAPIURL is the base URL of the Slack Web API.
+const DefaultAPIURL = "https://slack.com/api/"
+
+// API is a minimal client for the Slack Web API methods Slacker needs.
+type API struct {
+	Token  string       // bot or app level token, depending on the method.
+	URL    string       // defaults to DefaultAPIURL.
+	Client *http.Client // defaults to http.DefaultClient.
+}
+
+// APIError is returned when a Web API method fails.
+type APIError struct {
+	Method string
+	Code   string // such as "channel_not_found".
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slacker: %s failed: %s", e.Method, e.Code)
+}
+
+// Call invokes Web API `method` with `args` as its JSON body, and decodes the
+// response into `res` if it is not nil.
+func (a *API) Call(ctx context.Context, method string, args interface{}, res interface{}) error {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	url := a.URL
+	if url == "" {
+		url = DefaultAPIURL
+	}
+
+	req, err := http.NewRequest("POST", url+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return &APIError{Method: method, Code: resp.Status}
+	}
+
+	var raw json.RawMessage
+	err = json.NewDecoder(resp.Body).Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	err = json.Unmarshal(raw, &status)
+	if err != nil {
+		return err
+	}
+	if !status.OK {
+		return &APIError{Method: method, Code: status.Error}
+	}
+
+	if res == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, res)
+}
+
+// chatMessage is the body of chat.postMessage and chat.postEphemeral.
+type chatMessage struct {
+	Channel  string        `json:"channel"`
+	User     string        `json:"user,omitempty"`
+	ThreadTS string        `json:"thread_ts,omitempty"`
+	Text     string        `json:"text,omitempty"`
+	Blocks   []interface{} `json:"blocks,omitempty"`
+}
+
+// PostMessage posts `msg` to `channel`, in the thread of `threadTS` if it is
+// not empty.
+func (a *API) PostMessage(ctx context.Context, channel, threadTS string, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	return a.Call(ctx, "chat.postMessage", &chatMessage{
+		Channel:  channel,
+		ThreadTS: threadTS,
+		Text:     msg.Text,
+		Blocks:   msg.Blocks,
+	}, nil)
+}
+
+// PostEphemeral posts `msg` to `channel`, visible only to `user`, in the
+// thread of `threadTS` if it is not empty.
+func (a *API) PostEphemeral(ctx context.Context, channel, user, threadTS string, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	return a.Call(ctx, "chat.postEphemeral", &chatMessage{
+		Channel:  channel,
+		User:     user,
+		ThreadTS: threadTS,
+		Text:     msg.Text,
+		Blocks:   msg.Blocks,
+	}, nil)
+}
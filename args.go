@@ -0,0 +1,390 @@
+package slacker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindArgs returns a Handler which binds the words of a command's text, as
+// split by Tokenize, to a new T and invokes `handler` with it. T must be a
+// struct whose fields declare the arguments with `arg` tags:
+//
+//	type ScaleArgs struct {
+//		App      string        `arg:"app" help:"app to scale"`
+//		Replicas int           `arg:"replicas"`
+//		Env      string        `arg:"--env,enum=staging|production,default=staging"`
+//		Wait     time.Duration `arg:"--wait,default=30s"`
+//		Force    bool          `arg:"--force"`
+//	}
+//
+// A name without dashes declares a positional argument, bound in the order of
+// the fields, and a name with dashes declares a flag, given as "--name value"
+// or "--name=value". Positional arguments are required unless they have a
+// default or the "optional" option, which only the last ones may have. A
+// []string field captures the remaining positional arguments. The options
+// "enum=a|b" and "default=v" restrict the values and set a default. Tagged
+// fields must be exported, and may be strings, bools, ints, floats or
+// time.Durations.
+//
+// If the text cannot be bound, or is "help", the user is shown the error and
+// usage instead of invoking the handler. It panics if T is not a valid
+// arguments struct.
+func BindArgs[T any](handler func(io.Writer, *Command, *T) error) Handler {
+	spec, err := compileArgs(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(fmt.Sprintf("slacker: invalid arguments %T: %s", (*T)(nil), err))
+	}
+
+	return HandlerFunc(func(w io.Writer, cmd *Command) error {
+		tokens, err := cmd.Tokens()
+		if err == nil && len(tokens) == 1 && strings.EqualFold(tokens[0].Text, "help") {
+			rw := Response(w)
+			rw.SetResponseType(Ephemeral)
+			io.WriteString(rw, spec.usage(cmd))
+			return nil
+		}
+
+		args := new(T)
+		if err == nil {
+			err = spec.bind(reflect.ValueOf(args).Elem(), tokens)
+		}
+		if err != nil {
+			rw := Response(w)
+			rw.SetResponseType(Ephemeral)
+			fmt.Fprintf(rw, "%s.\n%s", err, spec.usage(cmd))
+			return nil
+		}
+		return handler(w, cmd, args)
+	})
+}
+
+// HandleArgs registers `handler` for command `name` with `token`, invoking it
+// with the command's text bound to a T as described by BindArgs.
+func HandleArgs[T any](s *Slacker, name, token string, handler func(io.Writer, *Command, *T) error) {
+	s.Handle(name, token, BindArgs(handler))
+}
+
+// argsSpec describes the arguments bound to a struct.
+type argsSpec struct {
+	positional []*argField
+	flags      []*argField
+}
+
+// argField is an argument bound to a field.
+type argField struct {
+	name     string // without dashes.
+	flag     bool
+	index    int
+	kind     reflect.Kind
+	duration bool
+	rest     bool
+	optional bool
+	choices  []string
+	def      string
+	help     string
+}
+
+// durationType is the type of time.Duration fields.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// compileArgs returns the spec of struct `t`.
+func compileArgs(t reflect.Type) (*argsSpec, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("not a struct")
+	}
+
+	spec := &argsSpec{}
+	seen := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("arg")
+		if !ok || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s is unexported", field.Name)
+		}
+
+		opts := strings.Split(tag, ",")
+		f := &argField{
+			name:  strings.TrimLeft(opts[0], "-"),
+			flag:  strings.HasPrefix(opts[0], "-"),
+			index: i,
+			kind:  field.Type.Kind(),
+			help:  field.Tag.Get("help"),
+		}
+		if f.name == "" {
+			return nil, fmt.Errorf("field %s has no name", field.Name)
+		}
+		if seen[f.name] {
+			return nil, fmt.Errorf("%s is declared twice", f.name)
+		}
+		seen[f.name] = true
+
+		for _, opt := range opts[1:] {
+			key, value, _ := strings.Cut(opt, "=")
+			switch key {
+			case "optional":
+				f.optional = true
+			case "default":
+				f.def = value
+				f.optional = true
+			case "enum":
+				f.choices = strings.Split(value, "|")
+			default:
+				return nil, fmt.Errorf("field %s has unknown option %q", field.Name, key)
+			}
+		}
+
+		switch {
+		case field.Type == durationType:
+			f.duration = true
+		case f.kind == reflect.Slice && field.Type.Elem().Kind() == reflect.String && !f.flag:
+			f.rest = true
+			f.optional = true
+		case f.kind == reflect.Bool && !f.flag:
+			return nil, fmt.Errorf("field %s is a bool, which must be a flag", field.Name)
+		case f.kind == reflect.String, f.kind == reflect.Bool:
+		case f.kind >= reflect.Int && f.kind <= reflect.Int64:
+		case f.kind >= reflect.Uint && f.kind <= reflect.Uint64:
+		case f.kind == reflect.Float32 || f.kind == reflect.Float64:
+		default:
+			return nil, fmt.Errorf("field %s has unsupported type %s", field.Name, field.Type)
+		}
+
+		if f.flag {
+			spec.flags = append(spec.flags, f)
+			continue
+		}
+		if n := len(spec.positional); n > 0 {
+			last := spec.positional[n-1]
+			if last.rest {
+				return nil, fmt.Errorf("%s follows %s, which captures the rest", f.name, last.name)
+			}
+			if last.optional && !f.optional {
+				return nil, fmt.Errorf("%s follows optional %s", f.name, last.name)
+			}
+		}
+		spec.positional = append(spec.positional, f)
+	}
+	return spec, nil
+}
+
+// fields returns every argument, positional arguments first.
+func (spec *argsSpec) fields() []*argField {
+	fields := make([]*argField, 0, len(spec.positional)+len(spec.flags))
+	fields = append(fields, spec.positional...)
+	return append(fields, spec.flags...)
+}
+
+// flag returns the flag named `name`, or nil.
+func (spec *argsSpec) flag(name string) *argField {
+	for _, f := range spec.flags {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// bind binds `tokens` to struct `v`.
+func (spec *argsSpec) bind(v reflect.Value, tokens []Token) error {
+	set := make(map[*argField]bool)
+
+	var positional []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		word := tok.Text
+		if word == "--" && !tok.Quoted {
+			for _, t := range tokens[i+1:] {
+				positional = append(positional, t.Text)
+			}
+			break
+		}
+		if tok.Quoted || !strings.HasPrefix(word, "--") || len(word) == 2 {
+			positional = append(positional, word)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(word[2:], "=")
+		f := spec.flag(name)
+		if f == nil {
+			return fmt.Errorf("unknown flag %s", word)
+		}
+		if !hasValue && f.kind == reflect.Bool {
+			value = "true"
+		} else if !hasValue {
+			if i+1 == len(tokens) {
+				return fmt.Errorf("%s requires a value", f)
+			}
+			i++
+			value = tokens[i].Text
+		}
+		err := f.set(v, value)
+		if err != nil {
+			return err
+		}
+		set[f] = true
+	}
+
+	for n, f := range spec.positional {
+		if f.rest {
+			if n < len(positional) {
+				v.Field(f.index).Set(reflect.ValueOf(positional[n:]).Convert(v.Field(f.index).Type()))
+			}
+			set[f] = true
+			positional = nil
+			break
+		}
+		if n >= len(positional) {
+			if !f.optional {
+				return fmt.Errorf("missing %s", f)
+			}
+			continue
+		}
+		err := f.set(v, positional[n])
+		if err != nil {
+			return err
+		}
+		set[f] = true
+	}
+	if len(positional) > len(spec.positional) {
+		return fmt.Errorf("unexpected %q", positional[len(spec.positional)])
+	}
+
+	for _, f := range spec.fields() {
+		if !set[f] && f.def != "" {
+			err := f.set(v, f.def)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// set parses `value` into the field of `v`.
+func (f *argField) set(v reflect.Value, value string) error {
+	field := v.Field(f.index)
+
+	if f.choices != nil {
+		choice, ok := matchChoice(value, f.choices)
+		if !ok {
+			return fmt.Errorf("%s must be one of %s, not %q", f, strings.Join(f.choices, ", "), value)
+		}
+		value = choice
+	}
+
+	switch {
+	case f.duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration such as 30s or 5m, not %q", f, value)
+		}
+		field.SetInt(int64(d))
+	case f.kind == reflect.String:
+		field.SetString(value)
+	case f.kind == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, not %q", f, value)
+		}
+		field.SetBool(b)
+	case f.kind >= reflect.Int && f.kind <= reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s must be a whole number, not %q", f, value)
+		}
+		field.SetInt(i)
+	case f.kind >= reflect.Uint && f.kind <= reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s must be a positive whole number, not %q", f, value)
+		}
+		field.SetUint(u)
+	case f.kind == reflect.Float32 || f.kind == reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s must be a number, not %q", f, value)
+		}
+		field.SetFloat(n)
+	}
+	return nil
+}
+
+// String returns how the argument is named in messages.
+func (f *argField) String() string {
+	if f.flag {
+		return "--" + f.name
+	}
+	return "<" + f.name + ">"
+}
+
+// placeholder returns how the value of the argument is shown in usage.
+func (f *argField) placeholder() string {
+	value := f.name
+	switch {
+	case f.choices != nil && f.flag:
+		value = strings.Join(f.choices, "|")
+	case f.choices != nil:
+		value += ":" + strings.Join(f.choices, "|")
+	case f.duration && f.flag:
+		value = "duration"
+	}
+
+	switch {
+	case f.flag:
+		return "<" + value + ">"
+	case f.rest:
+		return "[<" + value + ">...]"
+	case f.optional:
+		return "[<" + value + ">]"
+	default:
+		return "<" + value + ">"
+	}
+}
+
+// usage returns the usage message when handling `cmd`.
+func (spec *argsSpec) usage(cmd *Command) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage: %s", commandLine(cmd))
+	for _, f := range spec.positional {
+		b.WriteString(" " + f.placeholder())
+	}
+	for _, f := range spec.flags {
+		if f.kind == reflect.Bool {
+			fmt.Fprintf(&b, " [%s]", f)
+		} else {
+			fmt.Fprintf(&b, " [%s %s]", f, f.placeholder())
+		}
+	}
+	b.WriteString("\n")
+
+	var lines [][2]string
+	width := 0
+	for _, f := range spec.fields() {
+		if f.help == "" && f.def == "" {
+			continue
+		}
+		help := f.help
+		if f.def != "" {
+			help = strings.TrimSpace(fmt.Sprintf("%s (default %s)", help, f.def))
+		}
+		lines = append(lines, [2]string{f.String(), help})
+		if len(f.String()) > width {
+			width = len(f.String())
+		}
+	}
+	if len(lines) > 0 {
+		b.WriteString("\nArguments:\n")
+		for _, l := range lines {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, l[0], l[1])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package slacker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+type ScaleArgs struct {
+	App      string        `arg:"app" help:"app to scale"`
+	Replicas int           `arg:"replicas"`
+	Env      string        `arg:"--env,enum=staging|production,default=staging"`
+	Wait     time.Duration `arg:"--wait,default=30s"`
+	Force    bool          `arg:"--force"`
+	Reason   []string      `arg:"reason"`
+	Ignored  string
+}
+
+func newScaleServer(called *bool) *httptest.Server {
+	slack := slacker.New()
+	slacker.HandleArgs(slack, "scale", "foo", func(w io.Writer, cmd *slacker.Command, args *ScaleArgs) error {
+		*called = true
+		fmt.Fprintf(w, "%s=%d in %s wait=%s force=%v reason=%q", args.App, args.Replicas, args.Env, args.Wait, args.Force, strings.Join(args.Reason, " "))
+		return nil
+	})
+	return httptest.NewServer(slack)
+}
+
+func TestBindsArgs(t *testing.T) {
+	var called bool
+	server := newScaleServer(&called)
+	defer server.Close()
+
+	assert.Equal(t, `api=3 in staging wait=30s force=false reason=""`, postCommand(t, server.URL, "scale", "api 3").Text)
+	assert.Equal(t, `api=3 in production wait=1m0s force=true reason="load test"`, postCommand(t, server.URL, "scale", "--force api --env PRODUCTION 3 --wait=1m load test").Text)
+	assert.Equal(t, `api=-1 in staging wait=30s force=false reason="--env"`, postCommand(t, server.URL, "scale", "api -- -1 --env").Text)
+}
+
+func TestArgsBindingErrors(t *testing.T) {
+	var called bool
+	server := newScaleServer(&called)
+	defer server.Close()
+
+	usage := "Usage: /scale <app> <replicas> [<reason>...] [--env <staging|production>] [--wait <duration>] [--force]\n" +
+		"\n" +
+		"Arguments:\n" +
+		"  <app>   app to scale\n" +
+		"  --env   (default staging)\n" +
+		"  --wait  (default 30s)\n"
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"api", "missing <replicas>"},
+		{"api three", "<replicas> must be a whole number, not \"three\""},
+		{"api 3 --env prod", "--env must be one of staging, production, not \"prod\""},
+		{"api 3 --wait soon", "--wait must be a duration such as 30s or 5m, not \"soon\""},
+		{"api 3 --wait", "--wait requires a value"},
+		{"api 3 --force=maybe", "--force must be true or false, not \"maybe\""},
+		{"api 3 --quick", "unknown flag --quick"},
+	}
+	for _, test := range tests {
+		msg := postCommand(t, server.URL, "scale", test.text)
+		assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+		assert.Equal(t, test.expected+".\n"+usage, msg.Text)
+	}
+	assert.Equal(t, usage, postCommand(t, server.URL, "scale", "help").Text)
+	assert.Equal(t, false, called)
+}
+
+func TestBindArgsInRouter(t *testing.T) {
+	type Args struct {
+		Env  string `arg:"env,enum=staging|production"`
+		Note string `arg:"note,optional"`
+	}
+	r := slacker.NewRouter()
+	r.Handle("status", slacker.BindArgs(func(w io.Writer, cmd *slacker.Command, args *Args) error {
+		fmt.Fprintf(w, "%s %q", args.Env, args.Note)
+		return nil
+	}))
+
+	var b strings.Builder
+	r.HandleCommand(&b, &slacker.Command{Name: "deploy", Text: "status production"})
+	assert.Equal(t, `production ""`, b.String())
+
+	b.Reset()
+	r.HandleCommand(&b, &slacker.Command{Name: "deploy", Text: "status prod"})
+	assert.Equal(t, "<env> must be one of staging, production, not \"prod\".\nUsage: /deploy status <env:staging|production> [<note>]\n", b.String())
+}
+
+func TestInvalidArgsPanic(t *testing.T) {
+	type optionalFirst struct {
+		A string `arg:"a,optional"`
+		B string `arg:"b"`
+	}
+	type afterRest struct {
+		A []string `arg:"a"`
+		B string   `arg:"b"`
+	}
+	type positionalBool struct {
+		A bool `arg:"a"`
+	}
+	type unsupported struct {
+		A map[string]string `arg:"--a"`
+	}
+	type unknownOption struct {
+		A string `arg:"a,required"`
+	}
+	type duplicate struct {
+		A string `arg:"a"`
+		B string `arg:"--a"`
+	}
+	type unexported struct {
+		a string `arg:"a"`
+	}
+
+	for _, bind := range []func(){
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *optionalFirst) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *afterRest) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *positionalBool) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *unsupported) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *unknownOption) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *duplicate) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *unexported) error { return nil }) },
+		func() { slacker.BindArgs(func(io.Writer, *slacker.Command, *string) error { return nil }) },
+	} {
+		func() {
+			defer func() {
+				assert.NotEqual(t, nil, recover())
+			}()
+			bind()
+		}()
+	}
+}
+
+func TestBindsQuotedArgs(t *testing.T) {
+	var called bool
+	server := newScaleServer(&called)
+	defer server.Close()
+
+	assert.Equal(t, `web app=3 in staging wait=30s force=false reason="--force it’s busy"`, postCommand(t, server.URL, "scale", "“web app” 3 \"--force\" it’s busy").Text)
+	assert.T(t, strings.HasPrefix(postCommand(t, server.URL, "scale", "'web app 3").Text, "unterminated ' quote.\nUsage: /scale"))
+}
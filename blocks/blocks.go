@@ -0,0 +1,186 @@
+// Package blocks builds Slack Block Kit layouts.
+//
+// Each block and element marshals to the JSON Slack expects, including its
+// "type", so values can be added directly to a reply with
+// slacker.ResponseWriter.AddBlocks. Constructors take the fields Slack
+// requires; optional fields may be set on the returned value.
+package blocks
+
+import "encoding/json"
+
+// Block is a layout block.
+type Block interface {
+	BlockType() string
+}
+
+// SectionBlock displays text, fields and an optional accessory element.
+type SectionBlock struct {
+	Text      *Text   `json:"text,omitempty"`
+	Fields    []*Text `json:"fields,omitempty"`
+	Accessory Element `json:"accessory,omitempty"`
+	BlockID   string  `json:"block_id,omitempty"`
+}
+
+// Section returns a section block displaying `text`.
+func Section(text *Text) *SectionBlock {
+	return &SectionBlock{Text: text}
+}
+
+// Fields returns a section block displaying `fields` in columns.
+func Fields(fields ...*Text) *SectionBlock {
+	return &SectionBlock{Fields: fields}
+}
+
+// BlockType implements Block.
+func (SectionBlock) BlockType() string { return "section" }
+
+// MarshalJSON implements json.Marshaler.
+func (b SectionBlock) MarshalJSON() ([]byte, error) {
+	type section SectionBlock
+	return marshalType(b.BlockType(), section(b))
+}
+
+// ContextBlock displays small text and images.
+type ContextBlock struct {
+	Elements []Element `json:"elements"` // *Text or *ImageElement.
+	BlockID  string    `json:"block_id,omitempty"`
+}
+
+// Context returns a context block displaying `elements`, which should be text
+// or images.
+func Context(elements ...Element) *ContextBlock {
+	return &ContextBlock{Elements: elements}
+}
+
+// BlockType implements Block.
+func (ContextBlock) BlockType() string { return "context" }
+
+// MarshalJSON implements json.Marshaler.
+func (b ContextBlock) MarshalJSON() ([]byte, error) {
+	type context ContextBlock
+	return marshalType(b.BlockType(), context(b))
+}
+
+// DividerBlock separates blocks with a line.
+type DividerBlock struct {
+	BlockID string `json:"block_id,omitempty"`
+}
+
+// Divider returns a divider block.
+func Divider() *DividerBlock {
+	return &DividerBlock{}
+}
+
+// BlockType implements Block.
+func (DividerBlock) BlockType() string { return "divider" }
+
+// MarshalJSON implements json.Marshaler.
+func (b DividerBlock) MarshalJSON() ([]byte, error) {
+	type divider DividerBlock
+	return marshalType(b.BlockType(), divider(b))
+}
+
+// ActionsBlock holds interactive elements.
+type ActionsBlock struct {
+	Elements []Element `json:"elements"`
+	BlockID  string    `json:"block_id,omitempty"`
+}
+
+// Actions returns an actions block holding `elements`.
+func Actions(elements ...Element) *ActionsBlock {
+	return &ActionsBlock{Elements: elements}
+}
+
+// BlockType implements Block.
+func (ActionsBlock) BlockType() string { return "actions" }
+
+// MarshalJSON implements json.Marshaler.
+func (b ActionsBlock) MarshalJSON() ([]byte, error) {
+	type actions ActionsBlock
+	return marshalType(b.BlockType(), actions(b))
+}
+
+// HeaderBlock displays large plain text.
+type HeaderBlock struct {
+	Text    *Text  `json:"text"`
+	BlockID string `json:"block_id,omitempty"`
+}
+
+// Header returns a header block displaying `text`.
+func Header(text string) *HeaderBlock {
+	return &HeaderBlock{Text: PlainText(text)}
+}
+
+// BlockType implements Block.
+func (HeaderBlock) BlockType() string { return "header" }
+
+// MarshalJSON implements json.Marshaler.
+func (b HeaderBlock) MarshalJSON() ([]byte, error) {
+	type header HeaderBlock
+	return marshalType(b.BlockType(), header(b))
+}
+
+// ImageBlock displays an image.
+type ImageBlock struct {
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+	Title    *Text  `json:"title,omitempty"`
+	BlockID  string `json:"block_id,omitempty"`
+}
+
+// Image returns an image block displaying the image at `url`.
+func Image(url, altText string) *ImageBlock {
+	return &ImageBlock{ImageURL: url, AltText: altText}
+}
+
+// BlockType implements Block.
+func (ImageBlock) BlockType() string { return "image" }
+
+// MarshalJSON implements json.Marshaler.
+func (b ImageBlock) MarshalJSON() ([]byte, error) {
+	type image ImageBlock
+	return marshalType(b.BlockType(), image(b))
+}
+
+// InputBlock collects input in modals.
+type InputBlock struct {
+	Label          *Text   `json:"label"`
+	Element        Element `json:"element"`
+	Hint           *Text   `json:"hint,omitempty"`
+	Optional       bool    `json:"optional,omitempty"`
+	DispatchAction bool    `json:"dispatch_action,omitempty"`
+	BlockID        string  `json:"block_id,omitempty"`
+}
+
+// Input returns an input block labelled `label` collecting `element`.
+func Input(label string, element Element) *InputBlock {
+	return &InputBlock{Label: PlainText(label), Element: element}
+}
+
+// BlockType implements Block.
+func (InputBlock) BlockType() string { return "input" }
+
+// MarshalJSON implements json.Marshaler.
+func (b InputBlock) MarshalJSON() ([]byte, error) {
+	type input InputBlock
+	return marshalType(b.BlockType(), input(b))
+}
+
+// marshalType marshals `v`, which must marshal to a JSON object, with a "type"
+// of `typ` as its first member.
+func marshalType(typ string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	t, err := json.Marshal(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(`{"type":`), t...)
+	if len(b) > 2 {
+		out = append(out, ',')
+	}
+	return append(out, b[1:]...), nil
+}
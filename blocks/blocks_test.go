@@ -0,0 +1,92 @@
+package blocks_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker/blocks"
+)
+
+// Verify `v` marshals to `expected`.
+func testMarshal(t *testing.T, v interface{}, expected string) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("could not marshal with error: %s", err)
+	}
+	assert.Equal(t, expected, string(b))
+}
+
+func TestSection(t *testing.T) {
+	section := blocks.Section(blocks.Markdown("*Deploy* api"))
+	section.BlockID = "deploy"
+	section.Accessory = blocks.Thumbnail("https://example.com/api.png", "api")
+	testMarshal(t, section, `{"type":"section","text":{"type":"mrkdwn","text":"*Deploy* api"},"accessory":{"type":"image","image_url":"https://example.com/api.png","alt_text":"api"},"block_id":"deploy"}`)
+
+	fields := blocks.Fields(blocks.Markdown("*App*"), blocks.PlainText("api"))
+	testMarshal(t, fields, `{"type":"section","fields":[{"type":"mrkdwn","text":"*App*"},{"type":"plain_text","text":"api"}]}`)
+}
+
+func TestLayoutBlocks(t *testing.T) {
+	testMarshal(t, blocks.Divider(), `{"type":"divider"}`)
+	testMarshal(t, blocks.Header("Deploys"), `{"type":"header","text":{"type":"plain_text","text":"Deploys"}}`)
+	testMarshal(t, blocks.Image("https://example.com/graph.png", "graph"), `{"type":"image","image_url":"https://example.com/graph.png","alt_text":"graph"}`)
+	testMarshal(t, blocks.Context(blocks.Markdown("by alice")), `{"type":"context","elements":[{"type":"mrkdwn","text":"by alice"}]}`)
+
+	input := blocks.Input("Reason", blocks.PlainTextInput("reason"))
+	input.Optional = true
+	testMarshal(t, input, `{"type":"input","label":{"type":"plain_text","text":"Reason"},"element":{"type":"plain_text_input","action_id":"reason"},"optional":true}`)
+}
+
+func TestActions(t *testing.T) {
+	button := blocks.Button("rollback", "Rollback")
+	button.Style = blocks.Danger
+	button.Value = "api"
+	button.Confirm = blocks.Confirm("Are you sure?", blocks.Markdown("Rollback *api*?"), "Rollback", "Cancel")
+
+	actions := blocks.Actions(
+		button,
+		blocks.StaticSelect("env", "Environment", blocks.NewOption("Staging", "staging")),
+		blocks.DatePicker("date", "Date"),
+		blocks.Overflow("more", blocks.NewOption("Logs", "logs")),
+	)
+	testMarshal(t, actions, `{"type":"actions","elements":[`+
+		`{"type":"button","text":{"type":"plain_text","text":"Rollback"},"action_id":"rollback","value":"api","style":"danger","confirm":{"title":{"type":"plain_text","text":"Are you sure?"},"text":{"type":"mrkdwn","text":"Rollback *api*?"},"confirm":{"type":"plain_text","text":"Rollback"},"deny":{"type":"plain_text","text":"Cancel"}}},`+
+		`{"type":"static_select","action_id":"env","placeholder":{"type":"plain_text","text":"Environment"},"options":[{"text":{"type":"plain_text","text":"Staging"},"value":"staging"}]},`+
+		`{"type":"datepicker","action_id":"date","placeholder":{"type":"plain_text","text":"Date"}},`+
+		`{"type":"overflow","action_id":"more","options":[{"text":{"type":"plain_text","text":"Logs"},"value":"logs"}]}]}`)
+
+	testMarshal(t, blocks.UsersSelect("user", "User"), `{"type":"users_select","action_id":"user","placeholder":{"type":"plain_text","text":"User"}}`)
+	testMarshal(t, blocks.ChannelsSelect("channel", "Channel"), `{"type":"channels_select","action_id":"channel","placeholder":{"type":"plain_text","text":"Channel"}}`)
+	testMarshal(t, blocks.ConversationsSelect("conversation", "Conversation"), `{"type":"conversations_select","action_id":"conversation","placeholder":{"type":"plain_text","text":"Conversation"}}`)
+	testMarshal(t, blocks.ExternalSelect("app", "App"), `{"type":"external_select","action_id":"app","placeholder":{"type":"plain_text","text":"App"}}`)
+}
+
+func TestRichText(t *testing.T) {
+	rich := blocks.RichText(
+		blocks.RichTextSection(
+			blocks.RichTextText("Deployed ", nil),
+			blocks.RichTextText("api", &blocks.Style{Code: true}),
+			blocks.RichTextUser("U123"),
+			blocks.RichTextEmoji("tada"),
+		),
+		blocks.RichTextList(blocks.Bullet, blocks.RichTextSection(blocks.RichTextLink("https://example.com", "logs"))),
+		blocks.RichTextQuote(blocks.RichTextBroadcast("here")),
+		blocks.RichTextPreformatted(blocks.RichTextChannel("C456"), blocks.RichTextUsergroup("S789")),
+	)
+	testMarshal(t, rich, `{"type":"rich_text","elements":[`+
+		`{"type":"rich_text_section","elements":[{"type":"text","text":"Deployed "},{"type":"text","text":"api","style":{"code":true}},{"type":"user","user_id":"U123"},{"type":"emoji","name":"tada"}]},`+
+		`{"type":"rich_text_list","style":"bullet","elements":[{"type":"rich_text_section","elements":[{"type":"link","url":"https://example.com","text":"logs"}]}]},`+
+		`{"type":"rich_text_quote","elements":[{"type":"broadcast","range":"here"}]},`+
+		`{"type":"rich_text_preformatted","elements":[{"type":"channel","channel_id":"C456"},{"type":"usergroup","usergroup_id":"S789"}]}]}`)
+}
+
+func Example() {
+	b, _ := json.Marshal([]blocks.Block{
+		blocks.Section(blocks.Markdown("Deploy *api* to production?")),
+		blocks.Actions(blocks.Button("deploy", "Deploy")),
+	})
+	fmt.Println(string(b))
+	// Output: [{"type":"section","text":{"type":"mrkdwn","text":"Deploy *api* to production?"}},{"type":"actions","elements":[{"type":"button","text":{"type":"plain_text","text":"Deploy"},"action_id":"deploy"}]}]
+}
@@ -0,0 +1,79 @@
+package blocks
+
+// Text object types.
+const (
+	PlainTextType = "plain_text"
+	MarkdownType  = "mrkdwn"
+)
+
+// Text is a text object. It may also be used as an element of a context
+// block.
+type Text struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Emoji    bool   `json:"emoji,omitempty"`
+	Verbatim bool   `json:"verbatim,omitempty"`
+}
+
+// PlainText returns a plain_text object.
+func PlainText(text string) *Text {
+	return &Text{Type: PlainTextType, Text: text}
+}
+
+// Markdown returns a mrkdwn text object.
+func Markdown(text string) *Text {
+	return &Text{Type: MarkdownType, Text: text}
+}
+
+// ElementType implements Element.
+func (t Text) ElementType() string { return t.Type }
+
+// Option is an option of a select or overflow menu.
+type Option struct {
+	Text        *Text  `json:"text"`
+	Value       string `json:"value"`
+	Description *Text  `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// NewOption returns an option displaying `text` with `value`.
+func NewOption(text, value string) *Option {
+	return &Option{Text: PlainText(text), Value: value}
+}
+
+// OptionGroup groups options of a select menu under a label.
+type OptionGroup struct {
+	Label   *Text     `json:"label"`
+	Options []*Option `json:"options"`
+}
+
+// NewOptionGroup returns a group of `options` labelled `label`.
+func NewOptionGroup(label string, options ...*Option) *OptionGroup {
+	return &OptionGroup{Label: PlainText(label), Options: options}
+}
+
+// Styles of buttons and confirm dialogs.
+const (
+	Primary = "primary"
+	Danger  = "danger"
+)
+
+// ConfirmDialog asks the user to confirm an action before it is sent.
+type ConfirmDialog struct {
+	Title   *Text  `json:"title"`
+	Text    *Text  `json:"text"`
+	Confirm *Text  `json:"confirm"`
+	Deny    *Text  `json:"deny"`
+	Style   string `json:"style,omitempty"`
+}
+
+// Confirm returns a confirm dialog titled `title` asking `text`, with buttons
+// labelled `confirm` and `deny`.
+func Confirm(title string, text *Text, confirm, deny string) *ConfirmDialog {
+	return &ConfirmDialog{
+		Title:   PlainText(title),
+		Text:    text,
+		Confirm: PlainText(confirm),
+		Deny:    PlainText(deny),
+	}
+}
@@ -0,0 +1,231 @@
+package blocks
+
+// Element is a block element.
+type Element interface {
+	ElementType() string
+}
+
+// ButtonElement is a button.
+type ButtonElement struct {
+	Text               *Text          `json:"text"`
+	ActionID           string         `json:"action_id,omitempty"`
+	URL                string         `json:"url,omitempty"`
+	Value              string         `json:"value,omitempty"`
+	Style              string         `json:"style,omitempty"`
+	Confirm            *ConfirmDialog `json:"confirm,omitempty"`
+	AccessibilityLabel string         `json:"accessibility_label,omitempty"`
+}
+
+// Button returns a button labelled `text` which sends `actionID`.
+func Button(actionID, text string) *ButtonElement {
+	return &ButtonElement{ActionID: actionID, Text: PlainText(text)}
+}
+
+// ElementType implements Element.
+func (ButtonElement) ElementType() string { return "button" }
+
+// MarshalJSON implements json.Marshaler.
+func (e ButtonElement) MarshalJSON() ([]byte, error) {
+	type button ButtonElement
+	return marshalType(e.ElementType(), button(e))
+}
+
+// StaticSelectElement is a select menu of static options.
+type StaticSelectElement struct {
+	ActionID      string         `json:"action_id,omitempty"`
+	Placeholder   *Text          `json:"placeholder,omitempty"`
+	Options       []*Option      `json:"options,omitempty"`
+	OptionGroups  []*OptionGroup `json:"option_groups,omitempty"`
+	InitialOption *Option        `json:"initial_option,omitempty"`
+	Confirm       *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// StaticSelect returns a select menu of `options`.
+func StaticSelect(actionID, placeholder string, options ...*Option) *StaticSelectElement {
+	return &StaticSelectElement{ActionID: actionID, Placeholder: PlainText(placeholder), Options: options}
+}
+
+// ElementType implements Element.
+func (StaticSelectElement) ElementType() string { return "static_select" }
+
+// MarshalJSON implements json.Marshaler.
+func (e StaticSelectElement) MarshalJSON() ([]byte, error) {
+	type staticSelect StaticSelectElement
+	return marshalType(e.ElementType(), staticSelect(e))
+}
+
+// ExternalSelectElement is a select menu of options loaded from the app.
+type ExternalSelectElement struct {
+	ActionID       string         `json:"action_id,omitempty"`
+	Placeholder    *Text          `json:"placeholder,omitempty"`
+	InitialOption  *Option        `json:"initial_option,omitempty"`
+	MinQueryLength int            `json:"min_query_length,omitempty"`
+	Confirm        *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// ExternalSelect returns a select menu of options loaded from the app.
+func ExternalSelect(actionID, placeholder string) *ExternalSelectElement {
+	return &ExternalSelectElement{ActionID: actionID, Placeholder: PlainText(placeholder)}
+}
+
+// ElementType implements Element.
+func (ExternalSelectElement) ElementType() string { return "external_select" }
+
+// MarshalJSON implements json.Marshaler.
+func (e ExternalSelectElement) MarshalJSON() ([]byte, error) {
+	type externalSelect ExternalSelectElement
+	return marshalType(e.ElementType(), externalSelect(e))
+}
+
+// UsersSelectElement is a select menu of users.
+type UsersSelectElement struct {
+	ActionID    string         `json:"action_id,omitempty"`
+	Placeholder *Text          `json:"placeholder,omitempty"`
+	InitialUser string         `json:"initial_user,omitempty"`
+	Confirm     *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// UsersSelect returns a select menu of users.
+func UsersSelect(actionID, placeholder string) *UsersSelectElement {
+	return &UsersSelectElement{ActionID: actionID, Placeholder: PlainText(placeholder)}
+}
+
+// ElementType implements Element.
+func (UsersSelectElement) ElementType() string { return "users_select" }
+
+// MarshalJSON implements json.Marshaler.
+func (e UsersSelectElement) MarshalJSON() ([]byte, error) {
+	type usersSelect UsersSelectElement
+	return marshalType(e.ElementType(), usersSelect(e))
+}
+
+// ConversationsSelectElement is a select menu of conversations.
+type ConversationsSelectElement struct {
+	ActionID            string         `json:"action_id,omitempty"`
+	Placeholder         *Text          `json:"placeholder,omitempty"`
+	InitialConversation string         `json:"initial_conversation,omitempty"`
+	Confirm             *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// ConversationsSelect returns a select menu of conversations.
+func ConversationsSelect(actionID, placeholder string) *ConversationsSelectElement {
+	return &ConversationsSelectElement{ActionID: actionID, Placeholder: PlainText(placeholder)}
+}
+
+// ElementType implements Element.
+func (ConversationsSelectElement) ElementType() string { return "conversations_select" }
+
+// MarshalJSON implements json.Marshaler.
+func (e ConversationsSelectElement) MarshalJSON() ([]byte, error) {
+	type conversationsSelect ConversationsSelectElement
+	return marshalType(e.ElementType(), conversationsSelect(e))
+}
+
+// ChannelsSelectElement is a select menu of public channels.
+type ChannelsSelectElement struct {
+	ActionID       string         `json:"action_id,omitempty"`
+	Placeholder    *Text          `json:"placeholder,omitempty"`
+	InitialChannel string         `json:"initial_channel,omitempty"`
+	Confirm        *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// ChannelsSelect returns a select menu of public channels.
+func ChannelsSelect(actionID, placeholder string) *ChannelsSelectElement {
+	return &ChannelsSelectElement{ActionID: actionID, Placeholder: PlainText(placeholder)}
+}
+
+// ElementType implements Element.
+func (ChannelsSelectElement) ElementType() string { return "channels_select" }
+
+// MarshalJSON implements json.Marshaler.
+func (e ChannelsSelectElement) MarshalJSON() ([]byte, error) {
+	type channelsSelect ChannelsSelectElement
+	return marshalType(e.ElementType(), channelsSelect(e))
+}
+
+// DatePickerElement is a calendar for picking a date.
+type DatePickerElement struct {
+	ActionID    string         `json:"action_id,omitempty"`
+	Placeholder *Text          `json:"placeholder,omitempty"`
+	InitialDate string         `json:"initial_date,omitempty"` // YYYY-MM-DD.
+	Confirm     *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// DatePicker returns a date picker.
+func DatePicker(actionID, placeholder string) *DatePickerElement {
+	return &DatePickerElement{ActionID: actionID, Placeholder: PlainText(placeholder)}
+}
+
+// ElementType implements Element.
+func (DatePickerElement) ElementType() string { return "datepicker" }
+
+// MarshalJSON implements json.Marshaler.
+func (e DatePickerElement) MarshalJSON() ([]byte, error) {
+	type datePicker DatePickerElement
+	return marshalType(e.ElementType(), datePicker(e))
+}
+
+// OverflowElement is a compact menu of options.
+type OverflowElement struct {
+	ActionID string         `json:"action_id,omitempty"`
+	Options  []*Option      `json:"options"`
+	Confirm  *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// Overflow returns an overflow menu of `options`.
+func Overflow(actionID string, options ...*Option) *OverflowElement {
+	return &OverflowElement{ActionID: actionID, Options: options}
+}
+
+// ElementType implements Element.
+func (OverflowElement) ElementType() string { return "overflow" }
+
+// MarshalJSON implements json.Marshaler.
+func (e OverflowElement) MarshalJSON() ([]byte, error) {
+	type overflow OverflowElement
+	return marshalType(e.ElementType(), overflow(e))
+}
+
+// PlainTextInputElement is a text field, for use in input blocks.
+type PlainTextInputElement struct {
+	ActionID     string `json:"action_id,omitempty"`
+	Placeholder  *Text  `json:"placeholder,omitempty"`
+	InitialValue string `json:"initial_value,omitempty"`
+	Multiline    bool   `json:"multiline,omitempty"`
+	MinLength    int    `json:"min_length,omitempty"`
+	MaxLength    int    `json:"max_length,omitempty"`
+}
+
+// PlainTextInput returns a text field.
+func PlainTextInput(actionID string) *PlainTextInputElement {
+	return &PlainTextInputElement{ActionID: actionID}
+}
+
+// ElementType implements Element.
+func (PlainTextInputElement) ElementType() string { return "plain_text_input" }
+
+// MarshalJSON implements json.Marshaler.
+func (e PlainTextInputElement) MarshalJSON() ([]byte, error) {
+	type plainTextInput PlainTextInputElement
+	return marshalType(e.ElementType(), plainTextInput(e))
+}
+
+// ImageElement is an image, for use in sections and context blocks.
+type ImageElement struct {
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// Thumbnail returns an image element displaying the image at `url`.
+func Thumbnail(url, altText string) *ImageElement {
+	return &ImageElement{ImageURL: url, AltText: altText}
+}
+
+// ElementType implements Element.
+func (ImageElement) ElementType() string { return "image" }
+
+// MarshalJSON implements json.Marshaler.
+func (e ImageElement) MarshalJSON() ([]byte, error) {
+	type image ImageElement
+	return marshalType(e.ElementType(), image(e))
+}
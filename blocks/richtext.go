@@ -0,0 +1,259 @@
+package blocks
+
+// RichTextBlock displays formatted text.
+type RichTextBlock struct {
+	Elements []RichTextElement `json:"elements"`
+	BlockID  string            `json:"block_id,omitempty"`
+}
+
+// RichText returns a rich text block of `elements`.
+func RichText(elements ...RichTextElement) *RichTextBlock {
+	return &RichTextBlock{Elements: elements}
+}
+
+// BlockType implements Block.
+func (RichTextBlock) BlockType() string { return "rich_text" }
+
+// MarshalJSON implements json.Marshaler.
+func (b RichTextBlock) MarshalJSON() ([]byte, error) {
+	type richText RichTextBlock
+	return marshalType(b.BlockType(), richText(b))
+}
+
+// RichTextElement is a top level element of a rich text block.
+type RichTextElement interface {
+	RichTextType() string
+}
+
+// RichTextSectionElement is a paragraph of rich text.
+type RichTextSectionElement struct {
+	Elements []RichTextItem `json:"elements"`
+}
+
+// RichTextSection returns a paragraph of `items`.
+func RichTextSection(items ...RichTextItem) *RichTextSectionElement {
+	return &RichTextSectionElement{Elements: items}
+}
+
+// RichTextType implements RichTextElement.
+func (RichTextSectionElement) RichTextType() string { return "rich_text_section" }
+
+// MarshalJSON implements json.Marshaler.
+func (e RichTextSectionElement) MarshalJSON() ([]byte, error) {
+	type section RichTextSectionElement
+	return marshalType(e.RichTextType(), section(e))
+}
+
+// List styles.
+const (
+	Bullet  = "bullet"
+	Ordered = "ordered"
+)
+
+// RichTextListElement is a list of paragraphs.
+type RichTextListElement struct {
+	Style    string                    `json:"style"`
+	Elements []*RichTextSectionElement `json:"elements"`
+	Indent   int                       `json:"indent,omitempty"`
+}
+
+// RichTextList returns a list of `style` holding `items`.
+func RichTextList(style string, items ...*RichTextSectionElement) *RichTextListElement {
+	return &RichTextListElement{Style: style, Elements: items}
+}
+
+// RichTextType implements RichTextElement.
+func (RichTextListElement) RichTextType() string { return "rich_text_list" }
+
+// MarshalJSON implements json.Marshaler.
+func (e RichTextListElement) MarshalJSON() ([]byte, error) {
+	type list RichTextListElement
+	return marshalType(e.RichTextType(), list(e))
+}
+
+// RichTextPreformattedElement is a code block.
+type RichTextPreformattedElement struct {
+	Elements []RichTextItem `json:"elements"`
+}
+
+// RichTextPreformatted returns a code block of `items`.
+func RichTextPreformatted(items ...RichTextItem) *RichTextPreformattedElement {
+	return &RichTextPreformattedElement{Elements: items}
+}
+
+// RichTextType implements RichTextElement.
+func (RichTextPreformattedElement) RichTextType() string { return "rich_text_preformatted" }
+
+// MarshalJSON implements json.Marshaler.
+func (e RichTextPreformattedElement) MarshalJSON() ([]byte, error) {
+	type preformatted RichTextPreformattedElement
+	return marshalType(e.RichTextType(), preformatted(e))
+}
+
+// RichTextQuoteElement is a quote.
+type RichTextQuoteElement struct {
+	Elements []RichTextItem `json:"elements"`
+}
+
+// RichTextQuote returns a quote of `items`.
+func RichTextQuote(items ...RichTextItem) *RichTextQuoteElement {
+	return &RichTextQuoteElement{Elements: items}
+}
+
+// RichTextType implements RichTextElement.
+func (RichTextQuoteElement) RichTextType() string { return "rich_text_quote" }
+
+// MarshalJSON implements json.Marshaler.
+func (e RichTextQuoteElement) MarshalJSON() ([]byte, error) {
+	type quote RichTextQuoteElement
+	return marshalType(e.RichTextType(), quote(e))
+}
+
+// RichTextItem is an inline element of rich text.
+type RichTextItem interface {
+	RichTextItemType() string
+}
+
+// Style of rich text items.
+type Style struct {
+	Bold   bool `json:"bold,omitempty"`
+	Italic bool `json:"italic,omitempty"`
+	Strike bool `json:"strike,omitempty"`
+	Code   bool `json:"code,omitempty"`
+}
+
+// TextItem is a run of text.
+type TextItem struct {
+	Text  string `json:"text"`
+	Style *Style `json:"style,omitempty"`
+}
+
+// RichTextText returns a run of `text` with an optional `style`.
+func RichTextText(text string, style *Style) *TextItem {
+	return &TextItem{Text: text, Style: style}
+}
+
+// RichTextItemType implements RichTextItem.
+func (TextItem) RichTextItemType() string { return "text" }
+
+// MarshalJSON implements json.Marshaler.
+func (i TextItem) MarshalJSON() ([]byte, error) {
+	type text TextItem
+	return marshalType(i.RichTextItemType(), text(i))
+}
+
+// LinkItem is a link.
+type LinkItem struct {
+	URL   string `json:"url"`
+	Text  string `json:"text,omitempty"`
+	Style *Style `json:"style,omitempty"`
+}
+
+// RichTextLink returns a link to `url` displaying `text`.
+func RichTextLink(url, text string) *LinkItem {
+	return &LinkItem{URL: url, Text: text}
+}
+
+// RichTextItemType implements RichTextItem.
+func (LinkItem) RichTextItemType() string { return "link" }
+
+// MarshalJSON implements json.Marshaler.
+func (i LinkItem) MarshalJSON() ([]byte, error) {
+	type link LinkItem
+	return marshalType(i.RichTextItemType(), link(i))
+}
+
+// UserItem mentions a user.
+type UserItem struct {
+	UserID string `json:"user_id"`
+}
+
+// RichTextUser returns a mention of user `id`.
+func RichTextUser(id string) *UserItem {
+	return &UserItem{UserID: id}
+}
+
+// RichTextItemType implements RichTextItem.
+func (UserItem) RichTextItemType() string { return "user" }
+
+// MarshalJSON implements json.Marshaler.
+func (i UserItem) MarshalJSON() ([]byte, error) {
+	type user UserItem
+	return marshalType(i.RichTextItemType(), user(i))
+}
+
+// ChannelItem mentions a channel.
+type ChannelItem struct {
+	ChannelID string `json:"channel_id"`
+}
+
+// RichTextChannel returns a mention of channel `id`.
+func RichTextChannel(id string) *ChannelItem {
+	return &ChannelItem{ChannelID: id}
+}
+
+// RichTextItemType implements RichTextItem.
+func (ChannelItem) RichTextItemType() string { return "channel" }
+
+// MarshalJSON implements json.Marshaler.
+func (i ChannelItem) MarshalJSON() ([]byte, error) {
+	type channel ChannelItem
+	return marshalType(i.RichTextItemType(), channel(i))
+}
+
+// UsergroupItem mentions a user group.
+type UsergroupItem struct {
+	UsergroupID string `json:"usergroup_id"`
+}
+
+// RichTextUsergroup returns a mention of user group `id`.
+func RichTextUsergroup(id string) *UsergroupItem {
+	return &UsergroupItem{UsergroupID: id}
+}
+
+// RichTextItemType implements RichTextItem.
+func (UsergroupItem) RichTextItemType() string { return "usergroup" }
+
+// MarshalJSON implements json.Marshaler.
+func (i UsergroupItem) MarshalJSON() ([]byte, error) {
+	type usergroup UsergroupItem
+	return marshalType(i.RichTextItemType(), usergroup(i))
+}
+
+// EmojiItem is an emoji.
+type EmojiItem struct {
+	Name string `json:"name"`
+}
+
+// RichTextEmoji returns the emoji `name`, without colons.
+func RichTextEmoji(name string) *EmojiItem {
+	return &EmojiItem{Name: name}
+}
+
+// RichTextItemType implements RichTextItem.
+func (EmojiItem) RichTextItemType() string { return "emoji" }
+
+// MarshalJSON implements json.Marshaler.
+func (i EmojiItem) MarshalJSON() ([]byte, error) {
+	type emoji EmojiItem
+	return marshalType(i.RichTextItemType(), emoji(i))
+}
+
+// BroadcastItem is a special mention such as @here.
+type BroadcastItem struct {
+	Range string `json:"range"` // here, channel or everyone.
+}
+
+// RichTextBroadcast returns a special mention of `rng`.
+func RichTextBroadcast(rng string) *BroadcastItem {
+	return &BroadcastItem{Range: rng}
+}
+
+// RichTextItemType implements RichTextItem.
+func (BroadcastItem) RichTextItemType() string { return "broadcast" }
+
+// MarshalJSON implements json.Marshaler.
+func (i BroadcastItem) MarshalJSON() ([]byte, error) {
+	type broadcast BroadcastItem
+	return marshalType(i.RichTextItemType(), broadcast(i))
+}
@@ -0,0 +1,162 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// HandleMentions runs registered command handlers for messages which mention
+// the app, or which are sent to it in a direct message. "@app deploy api" in a
+// channel, or "deploy api" in a direct message, runs the handler of the
+// "deploy" command with the text "api". The handler's reply is posted with
+// `api`, which must hold a bot token, in the thread of the message.
+//
+// The app_mention and message.im events must be enabled for the app, and
+// requests must be sent to Events. Commands run this way have no
+// response_url, and their tokens are not checked. Only a mention of the app's
+// bot user is removed from the start of the text. The bot user is found in
+// the authorizations of events, or else with auth.test.
+func (s *Slacker) HandleMentions(api *API) {
+	bot := &botUser{api: api}
+
+	s.events.HandleEventFunc(EventAppMention, func(ctx context.Context, ev *Event) error {
+		m := ev.Data.(*AppMentionEvent)
+		botID, err := bot.id(ctx, ev.Envelope)
+		if err != nil {
+			return err
+		}
+		text := stripMention(m.Text, botID)
+		return s.mention(ctx, api, ev.Envelope.EventID, m.User, m.Channel, text, thread(m.TS, m.ThreadTS))
+	})
+
+	s.events.HandleEventFunc(EventMessage, func(ctx context.Context, ev *Event) error {
+		m := ev.Data.(*MessageEvent)
+		if m.ChannelType != "im" || m.Subtype != "" || m.BotID != "" {
+			return nil
+		}
+		botID, err := bot.id(ctx, ev.Envelope)
+		if err != nil {
+			return err
+		}
+		text := stripMention(m.Text, botID)
+		return s.mention(ctx, api, ev.Envelope.EventID, m.User, m.Channel, text, thread(m.TS, m.ThreadTS))
+	})
+}
+
+// botUser finds the user ID of the app's bot.
+type botUser struct {
+	api    *API
+	userID string // from auth.test.
+	sync.Mutex
+}
+
+// id returns the user ID of the bot from the authorizations of `env`, or else
+// from auth.test, whose result is kept.
+func (b *botUser) id(ctx context.Context, env *EventEnvelope) (string, error) {
+	for _, a := range env.Authorizations {
+		if a.IsBot && a.UserID != "" {
+			return a.UserID, nil
+		}
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.userID != "" {
+		return b.userID, nil
+	}
+	var res struct {
+		UserID string `json:"user_id"`
+	}
+	err := b.api.Call(ctx, "auth.test", struct{}{}, &res)
+	if err != nil {
+		return "", err
+	}
+	b.userID = res.UserID
+	return b.userID, nil
+}
+
+// stripMention removes a mention of user `userID` from the start of `text`,
+// along with the punctuation which may follow it, as in "@app: deploy".
+func stripMention(text, userID string) string {
+	rest := strings.TrimLeft(text, " \t\n")
+	rest, ok := strings.CutPrefix(rest, "<@"+userID)
+	if !ok || rest == "" || (rest[0] != '>' && rest[0] != '|') {
+		return text
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return text
+	}
+	return strings.TrimLeft(rest[end+1:], " \t\n:,")
+}
+
+// thread returns the ts of the thread to reply to a message in.
+func thread(ts, threadTS string) string {
+	if threadTS != "" {
+		return threadTS
+	}
+	return ts
+}
+
+// mention runs the command in `text`, without the mention of the app, sent by
+// `user` in `channel` in event `eventID`, and posts its reply in the thread of
+// `ts`. Events are handled in the background, so mention waits for the
+// handler.
+func (s *Slacker) mention(ctx context.Context, api *API, eventID, user, channel, text, ts string) error {
+	name, text := splitWord(text)
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		return nil
+	}
+
+	cmd := &Command{
+		Name:      name,
+		Text:      text,
+		UserID:    user,
+		ChannelID: channel,
+	}
+
+	h, ok := s.handler(cmd.Name)
+	if !ok {
+		msg := &Message{Text: fmt.Sprintf("Unknown command %q", cmd.Name)}
+		return api.PostEphemeral(ctx, channel, user, ts, msg)
+	}
+
+	s.track(cmd, eventID)
+	cmd.Logger().Info("received command", "text", cmd.Text, "channel", cmd.ChannelID)
+
+	done, _, err := s.start(ctx, h, cmd)
+	if err != nil {
+		return err
+	}
+	res := <-done
+	s.inflight.Done()
+
+	msg := s.reply(context.WithoutCancel(cmd.Context()), cmd, res)
+	if msg.empty() {
+		return nil
+	}
+
+	if msg.ResponseType == Ephemeral {
+		err = api.PostEphemeral(context.Background(), channel, user, ts, msg)
+	} else {
+		err = api.PostMessage(context.Background(), channel, ts, msg)
+	}
+	if err != nil {
+		cmd.Logger().Error("posting reply", "err", err)
+	}
+	return nil
+}
+
+// splitWord splits the first word from the rest of `text`.
+func splitWord(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	i := strings.IndexAny(text, " \t\n")
+	if i < 0 {
+		return text, ""
+	}
+	return text[:i], strings.TrimSpace(text[i+1:])
+}
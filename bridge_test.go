@@ -0,0 +1,155 @@
+package slacker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// webAPI records calls to Web API methods.
+type webAPI struct {
+	sync.Mutex
+	calls []apiCall
+}
+
+type apiCall struct {
+	Method string
+	Token  string
+	Args   map[string]interface{}
+}
+
+func (a *webAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.Lock()
+	defer a.Unlock()
+
+	var args map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&args)
+	a.calls = append(a.calls, apiCall{
+		Method: strings.TrimPrefix(r.URL.Path, "/"),
+		Token:  strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		Args:   args,
+	})
+	if r.URL.Path == "/auth.test" {
+		io.WriteString(w, `{"ok":true,"user_id":"UBOT"}`)
+		return
+	}
+	io.WriteString(w, `{"ok":true}`)
+}
+
+// Wait for `n` calls to be made to `a`.
+func (a *webAPI) wait(t *testing.T, n int) []apiCall {
+	for i := 0; i < 100; i++ {
+		a.Lock()
+		calls := a.calls
+		a.Unlock()
+		if len(calls) >= n {
+			return calls
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d calls", n)
+	return nil
+}
+
+func TestHandleMentions(t *testing.T) {
+	web := &webAPI{}
+	ws := httptest.NewServer(web)
+	defer ws.Close()
+
+	slack := newEventSlacker()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "Deploying %s for %s", cmd.Text, cmd.UserID)
+		return nil
+	})
+	slack.HandleMentions(&slacker.API{Token: "xoxb-bot", URL: ws.URL + "/"})
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	post := func(event map[string]interface{}) {
+		res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+			"type":           "event_callback",
+			"event":          event,
+			"authorizations": []interface{}{map[string]interface{}{"team_id": "T1", "user_id": "UBOT", "is_bot": true}},
+		})
+		assert.Equal(t, 200, res.StatusCode)
+	}
+
+	post(map[string]interface{}{"type": "app_mention", "user": "U1", "channel": "C1", "ts": "1.1", "text": "<@UBOT> deploy api"})
+	calls := web.wait(t, 1)
+	assert.Equal(t, apiCall{
+		Method: "chat.postMessage",
+		Token:  "xoxb-bot",
+		Args:   map[string]interface{}{"channel": "C1", "thread_ts": "1.1", "text": "Deploying api for U1"},
+	}, calls[0])
+
+	post(map[string]interface{}{"type": "message", "channel_type": "im", "user": "U2", "channel": "D1", "ts": "2.1", "thread_ts": "2.0", "text": "deploy web"})
+	calls = web.wait(t, 2)
+	assert.Equal(t, map[string]interface{}{"channel": "D1", "thread_ts": "2.0", "text": "Deploying web for U2"}, calls[1].Args)
+
+	post(map[string]interface{}{"type": "app_mention", "user": "U1", "channel": "C1", "ts": "3.1", "text": "<@UBOT> dance"})
+	calls = web.wait(t, 3)
+	assert.Equal(t, "chat.postEphemeral", calls[2].Method)
+	assert.Equal(t, map[string]interface{}{"channel": "C1", "user": "U1", "thread_ts": "3.1", "text": `Unknown command "dance"`}, calls[2].Args)
+
+	// Messages from bots and in channels are ignored.
+	post(map[string]interface{}{"type": "message", "channel_type": "im", "bot_id": "B1", "channel": "D1", "ts": "4.1", "text": "deploy api"})
+	post(map[string]interface{}{"type": "message", "channel_type": "channel", "user": "U1", "channel": "C1", "ts": "5.1", "text": "deploy api"})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 3, len(web.wait(t, 3)))
+
+	// Only mentions of the app's bot user are removed.
+	post(map[string]interface{}{"type": "message", "channel_type": "im", "user": "U2", "channel": "D1", "ts": "6.1", "text": "<@UALICE> deploy api"})
+	calls = web.wait(t, 4)
+	assert.Equal(t, "chat.postEphemeral", calls[3].Method)
+	assert.Equal(t, `Unknown command "<@UALICE>"`, calls[3].Args["text"])
+}
+
+func TestHandleMentionsFindsBotUser(t *testing.T) {
+	web := &webAPI{}
+	ws := httptest.NewServer(web)
+	defer ws.Close()
+
+	slack := newEventSlacker()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "Deploying %s", cmd.Text)
+		return nil
+	})
+	slack.HandleMentions(&slacker.API{Token: "xoxb-bot", URL: ws.URL + "/"})
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	for i, text := range []string{"<@UBOT|app>: deploy api", "<@UBOT> deploy web"} {
+		event := map[string]interface{}{"type": "app_mention", "user": "U1", "channel": "C1", "ts": "1.1", "text": text}
+		res := postEvent(t, ts.URL, "secret", map[string]interface{}{"type": "event_callback", "event": event})
+		assert.Equal(t, 200, res.StatusCode)
+		web.wait(t, i+2)
+	}
+
+	// The bot user is looked up once.
+	calls := web.wait(t, 3)
+	assert.Equal(t, 3, len(calls))
+	assert.Equal(t, "auth.test", calls[0].Method)
+	assert.Equal(t, "Deploying api", calls[1].Args["text"])
+	assert.Equal(t, "Deploying web", calls[2].Args["text"])
+}
+
+func TestAPIError(t *testing.T) {
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"ok":false,"error":"channel_not_found"}`)
+	}))
+	defer ws.Close()
+
+	api := &slacker.API{URL: ws.URL + "/"}
+	err := api.PostMessage(context.Background(), "C1", "", &slacker.Message{Text: "hello"})
+	assert.Equal(t, &slacker.APIError{Method: "chat.postMessage", Code: "channel_not_found"}, err)
+}
@@ -0,0 +1,136 @@
+package slacker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallbackPrefix begins the action_ids generated for callbacks.
+const CallbackPrefix = "slacker:cb:"
+
+// DefaultCallbackTTL is how long callbacks are kept when no TTL is given.
+const DefaultCallbackTTL = FollowupWindow
+
+// DefaultExpiredMessage replies to actions whose callback has expired.
+const DefaultExpiredMessage = "This action has expired."
+
+// CallbackStore stores callbacks until they expire.
+type CallbackStore interface {
+	// Put stores `h` under `id` for `ttl`.
+	Put(id string, h InteractionHandler, ttl time.Duration) error
+
+	// Get returns the handler stored under `id`, or false if there is none or
+	// it has expired.
+	Get(id string) (InteractionHandler, bool, error)
+}
+
+// MemoryStore is a CallbackStore which keeps callbacks in memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	swept   time.Time
+}
+
+// memoryEntry is a callback stored in a MemoryStore.
+type memoryEntry struct {
+	handler InteractionHandler
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Put implements CallbackStore.
+func (m *MemoryStore) Put(id string, h InteractionHandler, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.swept) > time.Minute {
+		for id, e := range m.entries {
+			if now.After(e.expires) {
+				delete(m.entries, id)
+			}
+		}
+		m.swept = now
+	}
+
+	m.entries[id] = memoryEntry{handler: h, expires: now.Add(ttl)}
+	return nil
+}
+
+// Get implements CallbackStore.
+func (m *MemoryStore) Get(id string) (InteractionHandler, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[id]
+	if !ok || time.Now().After(e.expires) {
+		delete(m.entries, id)
+		return nil, false, nil
+	}
+	return e.handler, true, nil
+}
+
+// Callback registers `h` to be invoked for actions on an element rendered
+// with the returned action_id, so that it can capture the state it needs.
+// The callback is kept for `ttl`, or DefaultCallbackTTL if `ttl` is zero.
+func (i *Interactions) Callback(ttl time.Duration, h InteractionHandler) (string, error) {
+	if ttl <= 0 {
+		ttl = DefaultCallbackTTL
+	}
+
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	id := CallbackPrefix + hex.EncodeToString(b)
+
+	err = i.Callbacks.Put(id, h, ttl)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// CallbackFunc registers `h` function as a callback.
+func (i *Interactions) CallbackFunc(ttl time.Duration, h func(context.Context, ResponseWriter, *Interaction) error) (string, error) {
+	return i.Callback(ttl, InteractionHandlerFunc(h))
+}
+
+// callback returns the callback for `actionID`, or a handler which replies
+// that the action has expired.
+func (i *Interactions) callback(actionID string) InteractionHandler {
+	h, ok, err := i.Callbacks.Get(actionID)
+	if err != nil {
+		return InteractionHandlerFunc(func(ctx context.Context, w ResponseWriter, in *Interaction) error {
+			return fmt.Errorf("loading callback: %s", err)
+		})
+	}
+	if ok {
+		return h
+	}
+
+	return InteractionHandlerFunc(func(ctx context.Context, w ResponseWriter, in *Interaction) error {
+		msg := i.ExpiredMessage
+		if msg == "" {
+			msg = DefaultExpiredMessage
+		}
+		w.SetResponseType(Ephemeral)
+		w.Message().Text = msg
+		return nil
+	})
+}
+
+// isCallback reports whether `actionID` was generated for a callback.
+func isCallback(actionID string) bool {
+	return strings.HasPrefix(actionID, CallbackPrefix)
+}
@@ -0,0 +1,90 @@
+package slacker_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+	"github.com/segmentio/go-slacker/blocks"
+)
+
+func TestCallbacks(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	slack := slacker.New()
+	interactions := slack.Interactions()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		app := cmd.Text
+		id, err := interactions.CallbackFunc(time.Minute, func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+			fmt.Fprintf(w, "Rolling back %s", app)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slacker.Response(w).AddBlocks(blocks.Actions(blocks.Button(id, "Rollback")))
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+	is := httptest.NewServer(interactions)
+	defer is.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("text", "api")
+	values.Add("token", "foo")
+
+	msg := postMessage(t, ts.URL, values)
+	elements := msg.Blocks[0].(map[string]interface{})["elements"].([]interface{})
+	id := elements[0].(map[string]interface{})["action_id"].(string)
+
+	click := func(id string) {
+		res := postInteraction(t, is.URL, map[string]interface{}{
+			"type":         "block_actions",
+			"token":        "foo",
+			"response_url": hs.URL,
+			"actions":      []interface{}{map[string]interface{}{"type": "button", "action_id": id}},
+		})
+		assert.Equal(t, 200, res.StatusCode)
+	}
+
+	click(id)
+	waitMessages(t, hook, 1)
+	click(slacker.CallbackPrefix + "unknown")
+
+	msgs := waitMessages(t, hook, 2)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, &slacker.Message{Text: "Rolling back api"}, msgs[0])
+	assert.Equal(t, &slacker.Message{Text: slacker.DefaultExpiredMessage, ResponseType: slacker.Ephemeral}, msgs[1])
+}
+
+func TestMemoryStoreExpires(t *testing.T) {
+	store := slacker.NewMemoryStore()
+	h := slacker.InteractionHandlerFunc(func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		return nil
+	})
+
+	assert.Equal(t, nil, store.Put("short", h, time.Millisecond))
+	assert.Equal(t, nil, store.Put("long", h, time.Minute))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err := store.Get("short")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, ok)
+
+	_, ok, err = store.Get("long")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, ok)
+
+	_, ok, _ = store.Get("unknown")
+	assert.Equal(t, false, ok)
+}
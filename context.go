@@ -0,0 +1,51 @@
+package slacker
+
+import (
+	"context"
+	"io"
+)
+
+// ContextHandler is a Handler which receives the command's context.
+//
+// The context carries the values of the request the command arrived on, and
+// is cancelled when the client goes away before the command is acknowledged,
+// when the command's time budget is spent, or when the Slacker shuts down.
+type ContextHandler interface {
+	HandleCommandContext(ctx context.Context, w io.Writer, cmd *Command) error
+}
+
+// ContextHandlerFunc convenience type.
+type ContextHandlerFunc func(ctx context.Context, w io.Writer, cmd *Command) error
+
+// HandleCommandContext invokes itself.
+func (h ContextHandlerFunc) HandleCommandContext(ctx context.Context, w io.Writer, cmd *Command) error {
+	return h(ctx, w, cmd)
+}
+
+// HandleCommand invokes itself with the command's context, so that a
+// ContextHandlerFunc may also be used as a Handler.
+func (h ContextHandlerFunc) HandleCommand(w io.Writer, cmd *Command) error {
+	return h(cmd.Context(), w, cmd)
+}
+
+// AdaptHandler adapts `h` to a ContextHandler. The context remains available
+// to `h` through Command.Context.
+func AdaptHandler(h Handler) ContextHandler {
+	if ch, ok := h.(ContextHandler); ok {
+		return ch
+	}
+	return handlerAdapter{h}
+}
+
+// handlerAdapter adapts a Handler to a ContextHandler.
+type handlerAdapter struct {
+	Handler
+}
+
+// HandleCommandContext invokes the Handler with `ctx` as the command's context.
+func (h handlerAdapter) HandleCommandContext(ctx context.Context, w io.Writer, cmd *Command) error {
+	if ctx != cmd.Context() {
+		cmd = cmd.WithContext(ctx)
+	}
+	return h.HandleCommand(w, cmd)
+}
@@ -0,0 +1,105 @@
+package slacker_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+type contextKey struct{}
+
+// Make a request for `command` to be served by `slack` directly, with `ctx`.
+func serveCommand(slack *slacker.Slacker, ctx context.Context, command string) *httptest.ResponseRecorder {
+	values := url.Values{}
+	values.Add("command", command)
+	values.Add("token", "foo")
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	slack.ServeHTTP(w, req.WithContext(ctx))
+	return w
+}
+
+func TestHandleContextFunc(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleContextFunc("hello", "foo", func(ctx context.Context, w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "Hello %s", ctx.Value(contextKey{}))
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "World")
+	w := serveCommand(slack, ctx, "/hello")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"text\":\"Hello World\"}\n", w.Body.String())
+}
+
+func TestAdaptHandler(t *testing.T) {
+	h := slacker.AdaptHandler(slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "Hello %s", cmd.Context().Value(contextKey{}))
+		return nil
+	}))
+
+	var buf strings.Builder
+	ctx := context.WithValue(context.Background(), contextKey{}, "World")
+	err := h.HandleCommandContext(ctx, &buf, &slacker.Command{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Hello World", buf.String())
+}
+
+func TestCancelsWhenClientGoesAway(t *testing.T) {
+	cancelled := make(chan error)
+	slack := slacker.New()
+	slack.HandleContextFunc("wait", "foo", func(ctx context.Context, w io.Writer, cmd *slacker.Command) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	serveCommand(slack, ctx, "/wait")
+	assert.Equal(t, context.Canceled, <-cancelled)
+}
+
+func TestShutdown(t *testing.T) {
+	started := make(chan struct{})
+	slack := slacker.New()
+	slack.AckTimeout = time.Millisecond
+	slack.HandleContextFunc("wait", "foo", func(ctx context.Context, w io.Writer, cmd *slacker.Command) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/wait")
+	values.Add("token", "foo")
+
+	testReply(t, ts.URL, values, slacker.DefaultAckMessage)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, slack.Shutdown(ctx))
+
+	res, err := http.PostForm(ts.URL, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	assert.Equal(t, 503, res.StatusCode)
+}
@@ -0,0 +1,190 @@
+package slacker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultAckTimeout leaves a margin within the 3 seconds Slack waits for a
+// reply to a command.
+const DefaultAckTimeout = 2500 * time.Millisecond
+
+// DefaultAckMessage acknowledges commands which outlive the AckTimeout.
+const DefaultAckMessage = "Working on it…"
+
+// DefaultTimeout is the time budget of a command, which matches how long its
+// response_url accepts delayed responses.
+const DefaultTimeout = FollowupWindow
+
+// result of running a handler.
+type result struct {
+	res response
+	err error
+}
+
+// ackMessage returns the acknowledgement for slow commands.
+func (s *Slacker) ackMessage() string {
+	if s.AckMessage == "" {
+		return DefaultAckMessage
+	}
+	return s.AckMessage
+}
+
+// timeout returns the time budget for command `name`.
+func (s *Slacker) timeout(name string) time.Duration {
+	if t, ok := s.commands().timeouts[name]; ok && t > 0 {
+		return t
+	}
+	return DefaultTimeout
+}
+
+// errShuttingDown is returned for commands which arrive after Shutdown.
+var errShuttingDown = errors.New("slacker: shutting down")
+
+// begin counts work which Shutdown waits for, unless Slacker is shutting
+// down. The caller must call s.inflight.Done once the work is finished.
+func (s *Slacker) begin() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ctx.Err() != nil {
+		return errShuttingDown
+	}
+	s.inflight.Add(1)
+	return nil
+}
+
+// background runs `fn` in the background, with a context which is cancelled
+// with `parent` or by Shutdown, which waits for `fn` to return. An error is
+// returned if Slacker is shutting down.
+func (s *Slacker) background(parent context.Context, fn func(ctx context.Context)) error {
+	err := s.begin()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	stop := context.AfterFunc(s.ctx, cancel)
+	go func() {
+		defer s.inflight.Done()
+		defer cancel()
+		defer stop()
+		fn(ctx)
+	}()
+	return nil
+}
+
+// start invokes `h` for `cmd` in the background, within the command's time
+// budget. The command's context carries the values of `parent` and the
+// command's request logger, but is not cancelled with it. Once it has
+// received the result, the caller must call s.inflight.Done.
+func (s *Slacker) start(parent context.Context, h ContextHandler, cmd *Command) (<-chan *result, context.CancelFunc, error) {
+	err := s.begin()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := WithLogger(context.WithoutCancel(parent), cmd.Logger())
+	ctx, cancel := context.WithTimeout(ctx, s.timeout(cmd.Name))
+	stop := context.AfterFunc(s.ctx, cancel)
+	cmd.ctx = ctx
+
+	done := make(chan *result, 1)
+	go func() {
+		defer cancel()
+		defer stop()
+		done <- s.invoke(ctx, h, cmd)
+	}()
+	return done, cancel, nil
+}
+
+// run invokes `h` for `cmd` on behalf of request `r`, and returns the result
+// if the handler finishes before the AckTimeout. Otherwise the command is
+// acknowledged and run returns nil. It also returns nil if the command is
+// abandoned because Slacker is shutting down or the client went away.
+func (s *Slacker) run(w http.ResponseWriter, r *http.Request, h ContextHandler, cmd *Command) *result {
+	res, err := s.await(r.Context(), h, cmd)
+	if err == errShuttingDown {
+		http.Error(w, "Shutting down", 503)
+		return nil
+	}
+	if err != nil || res != nil {
+		return res
+	}
+
+	err = writeMessage(w, &Message{Text: s.ackMessage()})
+	if err != nil {
+		cmd.Logger().Error("writing acknowledgement", "err", err)
+	}
+	return nil
+}
+
+// await invokes `h` for `cmd` within the command's time budget, and returns
+// the result if the handler finishes before the AckTimeout. Otherwise the
+// handler's eventual output is posted to the response_url, and await returns
+// a nil result so that the caller acknowledges the command. An error is
+// returned if Slacker is shutting down, or if `ctx` is done first, in which
+// case the command is abandoned.
+func (s *Slacker) await(ctx context.Context, h ContextHandler, cmd *Command) (*result, error) {
+	done, cancel, err := s.start(ctx, h, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	wait := s.AckTimeout
+	if wait <= 0 {
+		wait = DefaultAckTimeout
+	}
+	if timeout := s.timeout(cmd.Name); timeout < wait {
+		wait = timeout
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		s.inflight.Done()
+		return res, nil
+	case <-ctx.Done():
+		cancel()
+		go s.abandon(cmd, done)
+		return nil, ctx.Err()
+	case <-timer.C:
+		go s.deliver(cmd, done)
+		return nil, nil
+	}
+}
+
+// abandon waits for the handler of `cmd`, whose client went away.
+func (s *Slacker) abandon(cmd *Command, done <-chan *result) {
+	defer s.inflight.Done()
+
+	res := <-done
+	if res.err != nil {
+		cmd.done("abandoned", "err", res.err)
+	} else {
+		cmd.done("abandoned")
+	}
+}
+
+// deliver posts the result of a handler which outlived the AckTimeout.
+func (s *Slacker) deliver(cmd *Command, done <-chan *result) {
+	defer s.inflight.Done()
+
+	res := <-done
+
+	// The command's context is cancelled once its handler returns, but keeps
+	// the values which reporters may need.
+	msg := s.reply(context.WithoutCancel(cmd.Context()), cmd, res)
+	if msg.empty() {
+		return
+	}
+
+	err := cmd.Followup(context.Background(), msg)
+	if err != nil {
+		cmd.Logger().Error("posting delayed response", "err", err)
+	}
+}
@@ -0,0 +1,113 @@
+package slacker_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// Wait for `n` messages to be posted to `hook`.
+func waitMessages(t *testing.T, hook *responseURL, n int) []*slacker.Message {
+	for i := 0; i < 100; i++ {
+		if msgs := hook.Messages(); len(msgs) >= n {
+			return msgs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d messages", n)
+	return nil
+}
+
+func TestRepliesWithinAckTimeout(t *testing.T) {
+	slack := slacker.New()
+	slack.AckTimeout = time.Second
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		_, ok := cmd.Context().Deadline()
+		assert.Equal(t, true, ok)
+		fmt.Fprint(w, "Hello World")
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+
+	testReply(t, ts.URL, values, "Hello World")
+}
+
+func TestAcknowledgesSlowCommands(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	reported := make(chan error, 1)
+	slack := slacker.New()
+	slack.AckTimeout = 10 * time.Millisecond
+	slack.AckMessage = "Deploying…"
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		reported <- ctx.Err()
+	})
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "Deployed")
+		return nil
+	})
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		time.Sleep(50 * time.Millisecond)
+		return fmt.Errorf("something exploded")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("response_url", hs.URL)
+
+	testReply(t, ts.URL, values, "Deploying…")
+	msgs := waitMessages(t, hook, 1)
+	assert.Equal(t, "Deployed", msgs[0].Text)
+
+	values.Set("command", "/boom")
+	testReply(t, ts.URL, values, "Deploying…")
+	msgs = waitMessages(t, hook, 2)
+	assert.T(t, strings.HasPrefix(msgs[1].Text, "Sorry, that didn't work."))
+	assert.Equal(t, nil, <-reported)
+}
+
+func TestCancelsAfterTimeout(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	slack := slacker.New()
+	slack.SetTimeout("wait", 20*time.Millisecond)
+	slack.HandleFunc("wait", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		<-cmd.Context().Done()
+		fmt.Fprint(w, "Gave up")
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/wait")
+	values.Add("token", "foo")
+	values.Add("response_url", hs.URL)
+
+	// The acknowledgement is sent early when the budget is shorter than the
+	// AckTimeout.
+	testReply(t, ts.URL, values, slacker.DefaultAckMessage)
+	msgs := waitMessages(t, hook, 1)
+	assert.Equal(t, "Gave up", msgs[0].Text)
+}
@@ -0,0 +1,101 @@
+package slacker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docopt/docopt-go"
+)
+
+// Docopt returns a Handler which parses the words of a command's text, as
+// split by Tokenize, with the docopt `usage` string, and invokes `handler`
+// with a copy of the command whose Args hold the parsed arguments, keyed as
+// docopt keys them:
+//
+//	Usage:
+//	  deploy <app> [--env=<env>] [--force]
+//	  deploy rollback <app>
+//
+// The program name in the usage patterns is ignored, so they may name the
+// command with or without its leading slash. If the text is "help", includes
+// -h or --help, or does not match the usage, the user is shown the usage
+// instead of invoking the handler.
+func Docopt(usage string, handler Handler) Handler {
+	return HandlerFunc(func(w io.Writer, cmd *Command) error {
+		tokens, err := cmd.Tokens()
+		if err != nil {
+			writeUsage(w, usage, err)
+			return nil
+		}
+
+		// docopt parses the process's arguments when argv is nil.
+		argv := []string{}
+		for _, tok := range tokens {
+			argv = append(argv, tok.Text)
+		}
+		if docoptHelp(argv) {
+			writeUsage(w, usage, nil)
+			return nil
+		}
+
+		args, err := docoptParser.ParseArgs(usage, argv, "")
+		if _, ok := err.(*docopt.UserError); ok {
+			writeUsage(w, usage, nil)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid usage for %s: %s", commandLine(cmd), err)
+		}
+
+		c := *cmd
+		c.Args = args
+		return handler.HandleCommand(w, &c)
+	})
+}
+
+// docoptParser parses commands' text without printing usage or exiting on
+// errors, which are shown to the user instead. docopt-go replaces the
+// tj/docopt fork, whose Parse always prints usage errors to stdout.
+var docoptParser = &docopt.Parser{
+	HelpHandler:   docopt.NoHelpHandler,
+	SkipHelpFlags: true,
+}
+
+// HandleDocopt registers `handler` for command `name` with `token`, invoking
+// it with the command's text parsed by the docopt `usage` string as described
+// by Docopt.
+func (s *Slacker) HandleDocopt(name, token, usage string, handler Handler) {
+	s.Handle(name, token, Docopt(usage, handler))
+}
+
+// HandleDocoptFunc registers `handler` function for command `name` with
+// `token`, invoking it with the command's text parsed by the docopt `usage`
+// string.
+func (s *Slacker) HandleDocoptFunc(name, token, usage string, handler func(io.Writer, *Command) error) {
+	s.HandleDocopt(name, token, usage, HandlerFunc(handler))
+}
+
+// docoptHelp reports whether `argv` asks for help.
+func docoptHelp(argv []string) bool {
+	if len(argv) == 1 && strings.EqualFold(argv[0], "help") {
+		return true
+	}
+	for _, arg := range argv {
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeUsage writes `usage` to `w` as an ephemeral code block, following
+// `err` if it is not nil.
+func writeUsage(w io.Writer, usage string, err error) {
+	rw := Response(w)
+	rw.SetResponseType(Ephemeral)
+	if err != nil {
+		fmt.Fprintf(rw, "%s.\n", err)
+	}
+	fmt.Fprintf(rw, "```\n%s\n```", strings.Trim(usage, "\n"))
+}
@@ -0,0 +1,90 @@
+package slacker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+const deployUsage = `
+Usage:
+  /deploy <app> [--env=<env>] [--force]
+  /deploy rollback <app>
+
+Options:
+  --env=<env>  environment [default: staging]
+  --force      skip checks`
+
+func newDocoptServer(called *bool) *httptest.Server {
+	slack := slacker.New()
+	slack.HandleDocoptFunc("deploy", "foo", deployUsage, func(w io.Writer, cmd *slacker.Command) error {
+		*called = true
+		if cmd.Args["rollback"] == true {
+			fmt.Fprintf(w, "rolling back %s", cmd.Arg("<app>"))
+			return nil
+		}
+		fmt.Fprintf(w, "deploying %s to %s force=%v", cmd.Arg("<app>"), cmd.Arg("--env"), cmd.Args["--force"])
+		return nil
+	})
+	return httptest.NewServer(slack)
+}
+
+func TestDocoptParsesArgs(t *testing.T) {
+	var called bool
+	server := newDocoptServer(&called)
+	defer server.Close()
+
+	assert.Equal(t, "deploying api to staging force=false", postCommand(t, server.URL, "deploy", "api").Text)
+	assert.Equal(t, "deploying api to production force=true", postCommand(t, server.URL, "deploy", "--force api --env=production").Text)
+	assert.Equal(t, "rolling back api", postCommand(t, server.URL, "deploy", "rollback api").Text)
+}
+
+func TestDocoptShowsUsage(t *testing.T) {
+	var called bool
+	server := newDocoptServer(&called)
+	defer server.Close()
+
+	usage := "```\nUsage:\n  /deploy <app> [--env=<env>] [--force]\n  /deploy rollback <app>\n\nOptions:\n  --env=<env>  environment [default: staging]\n  --force      skip checks\n```"
+	for _, text := range []string{"help", "api --help", "-h", "", "api web", "api --quick"} {
+		msg := postCommand(t, server.URL, "deploy", text)
+		assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+		assert.Equal(t, usage, msg.Text)
+	}
+	assert.Equal(t, false, called)
+}
+
+func TestDocoptTokenizesText(t *testing.T) {
+	var called bool
+	server := newDocoptServer(&called)
+	defer server.Close()
+
+	assert.Equal(t, "deploying web app to staging force=false", postCommand(t, server.URL, "deploy", "“web app”").Text)
+	assert.T(t, strings.HasPrefix(postCommand(t, server.URL, "deploy", "“web app").Text, "unterminated “ quote.\n```\nUsage:"))
+}
+
+func TestDocoptDoesNotPrint(t *testing.T) {
+	handler := slacker.Docopt(deployUsage, slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+		return nil
+	}))
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("could not create pipe with error: %s", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	for _, text := range []string{"help", "api web", "--version"} {
+		handler.HandleCommand(slacker.NewRecorder(), &slacker.Command{Name: "deploy", Text: text})
+	}
+	os.Stdout = stdout
+	w.Close()
+
+	out, _ := io.ReadAll(r)
+	assert.Equal(t, "", string(out))
+}
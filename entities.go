@@ -0,0 +1,175 @@
+package slacker
+
+import (
+	"strings"
+)
+
+// EntityType is the type of an entity in a command's text.
+type EntityType string
+
+// Types of entities.
+const (
+	EntityUser      EntityType = "user"      // <@U123|alice>
+	EntityChannel   EntityType = "channel"   // <#C123|general>
+	EntityUsergroup EntityType = "usergroup" // <!subteam^S123|@oncall>
+	EntitySpecial   EntityType = "special"   // <!here>, or a command such as <!date^1392734382^{date}|Feb 18>
+	EntityLink      EntityType = "link"      // <https://example.com|label>
+)
+
+// Entity is a mention or link which Slack encodes in the text of a command,
+// such as "<@U123|alice>".
+type Entity struct {
+	Type EntityType
+
+	// ID is the ID of the user, channel or usergroup, the name of the special
+	// mention such as "here" or of another special command such as
+	// "date^1392734382^{date}", or the URL of a link.
+	ID string
+
+	// Label is the text Slack shows for the entity, such as "alice", which
+	// may be empty.
+	Label string
+
+	// Start and End are the byte offsets of the entity in the text.
+	Start, End int
+}
+
+// Text returns how the entity reads as plain text, such as "@alice".
+func (e *Entity) Text() string {
+	switch e.Type {
+	case EntityUser:
+		return "@" + firstNonEmpty(e.Label, e.ID)
+	case EntityChannel:
+		return "#" + firstNonEmpty(e.Label, e.ID)
+	case EntityUsergroup:
+		if strings.HasPrefix(e.Label, "@") {
+			return e.Label
+		}
+		return "@" + firstNonEmpty(e.Label, e.ID)
+	case EntitySpecial:
+		switch e.ID {
+		case "here", "channel", "everyone":
+			return "@" + e.ID
+		}
+		return firstNonEmpty(e.Label, e.ID)
+	default:
+		return firstNonEmpty(e.Label, e.ID)
+	}
+}
+
+// ParseEntities returns the entities in `text`, in order.
+func ParseEntities(text string) []*Entity {
+	var entities []*Entity
+	for i := 0; i < len(text); {
+		start := strings.IndexByte(text[i:], '<')
+		if start < 0 {
+			break
+		}
+		start += i
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		if e := parseEntity(text[start+1 : end-1]); e != nil {
+			e.Start, e.End = start, end
+			entities = append(entities, e)
+		}
+		i = end
+	}
+	return entities
+}
+
+// parseEntity parses the contents of an entity, such as "@U123|alice".
+func parseEntity(s string) *Entity {
+	id, label, _ := strings.Cut(s, "|")
+	label = unescapeEntities(label)
+
+	switch {
+	case strings.HasPrefix(id, "@"):
+		return &Entity{Type: EntityUser, ID: id[1:], Label: label}
+	case strings.HasPrefix(id, "#"):
+		return &Entity{Type: EntityChannel, ID: id[1:], Label: label}
+	case strings.HasPrefix(id, "!subteam^"):
+		return &Entity{Type: EntityUsergroup, ID: strings.TrimPrefix(id, "!subteam^"), Label: label}
+	case strings.HasPrefix(id, "!"):
+		return &Entity{Type: EntitySpecial, ID: id[1:], Label: label}
+	case id != "":
+		return &Entity{Type: EntityLink, ID: unescapeEntities(id), Label: label}
+	default:
+		return nil
+	}
+}
+
+// PlainText returns `text` with its entities replaced by their plain text,
+// such as "@alice", and the HTML entities Slack escapes decoded.
+func PlainText(text string) string {
+	var b strings.Builder
+	i := 0
+	for _, e := range ParseEntities(text) {
+		b.WriteString(unescapeEntities(text[i:e.Start]))
+		b.WriteString(e.Text())
+		i = e.End
+	}
+	b.WriteString(unescapeEntities(text[i:]))
+	return b.String()
+}
+
+// Entities returns the entities in the command's text.
+func (cmd *Command) Entities() []*Entity {
+	return ParseEntities(cmd.Text)
+}
+
+// Users returns the users mentioned in the command's text.
+func (cmd *Command) Users() []*Entity {
+	return cmd.entities(EntityUser)
+}
+
+// Channels returns the channels referenced in the command's text.
+func (cmd *Command) Channels() []*Entity {
+	return cmd.entities(EntityChannel)
+}
+
+// Usergroups returns the usergroups mentioned in the command's text.
+func (cmd *Command) Usergroups() []*Entity {
+	return cmd.entities(EntityUsergroup)
+}
+
+// Specials returns the special mentions, such as @here, in the command's
+// text.
+func (cmd *Command) Specials() []*Entity {
+	return cmd.entities(EntitySpecial)
+}
+
+// Links returns the links in the command's text.
+func (cmd *Command) Links() []*Entity {
+	return cmd.entities(EntityLink)
+}
+
+// PlainText returns the command's text with its entities replaced by their
+// plain text.
+func (cmd *Command) PlainText() string {
+	return PlainText(cmd.Text)
+}
+
+// entities returns the entities of type `typ` in the command's text.
+func (cmd *Command) entities(typ EntityType) []*Entity {
+	var entities []*Entity
+	for _, e := range cmd.Entities() {
+		if e.Type == typ {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+// firstNonEmpty returns the first of `s` which is not empty.
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package slacker_test
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func TestCommandEntities(t *testing.T) {
+	cmd := &slacker.Command{Text: "<@U123|alice> and <@W456> in <#C456|ops> <!subteam^S789|@oncall> <!here> see <https://x.com/?a=1&amp;b=2|runbook &amp; notes> or <mailto:bob@x.com>"}
+
+	assert.Equal(t, []*slacker.Entity{
+		{Type: slacker.EntityUser, ID: "U123", Label: "alice", Start: 0, End: 13},
+		{Type: slacker.EntityUser, ID: "W456", Start: 18, End: 25},
+	}, cmd.Users())
+	assert.Equal(t, []*slacker.Entity{{Type: slacker.EntityChannel, ID: "C456", Label: "ops", Start: 29, End: 40}}, cmd.Channels())
+	assert.Equal(t, []*slacker.Entity{{Type: slacker.EntityUsergroup, ID: "S789", Label: "@oncall", Start: 41, End: 64}}, cmd.Usergroups())
+	assert.Equal(t, []*slacker.Entity{{Type: slacker.EntitySpecial, ID: "here", Start: 65, End: 72}}, cmd.Specials())
+
+	links := cmd.Links()
+	assert.Equal(t, 2, len(links))
+	assert.Equal(t, "https://x.com/?a=1&b=2", links[0].ID)
+	assert.Equal(t, "runbook & notes", links[0].Label)
+	assert.Equal(t, "mailto:bob@x.com", links[1].ID)
+	assert.Equal(t, "", links[1].Label)
+
+	assert.Equal(t, 7, len(cmd.Entities()))
+	for _, e := range cmd.Entities() {
+		assert.Equal(t, byte('<'), cmd.Text[e.Start])
+		assert.Equal(t, byte('>'), cmd.Text[e.End-1])
+	}
+}
+
+func TestCommandPlainText(t *testing.T) {
+	cmd := &slacker.Command{Text: "/page <@U123|alice> and <@U456> in <#C456|ops> cc <!subteam^S789|@oncall> <!channel> &lt;urgent&gt; see <https://x.com|runbook> or <https://y.com>"}
+	assert.Equal(t, "/page @alice and @U456 in #ops cc @oncall @channel <urgent> see runbook or https://y.com", cmd.PlainText())
+
+	cmd = &slacker.Command{Text: "due <!date^1392734382^{date_short}|Feb 18, 2014> cc <!here|here>"}
+	assert.Equal(t, "due Feb 18, 2014 cc @here", cmd.PlainText())
+
+	cmd = &slacker.Command{Text: "no entities &amp; an unterminated <@U123"}
+	assert.Equal(t, 0, len(cmd.Entities()))
+	assert.Equal(t, "no entities & an unterminated <@U123", cmd.PlainText())
+}
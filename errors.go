@@ -0,0 +1,155 @@
+package slacker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultErrorMessage replaces the error of a handler which is not a
+// UserError. It is formatted with the ID of the incident.
+const DefaultErrorMessage = "Sorry, that didn't work. If it keeps happening, please report incident %s."
+
+// UserError is an error whose message is meant for the user who sent the
+// command. Handlers return it to reply with the message rather than an
+// apology, without the command being reported as an incident.
+type UserError struct {
+	// Message is shown to the user.
+	Message string
+
+	// Hint follows the message, such as to suggest what to do instead.
+	Hint string
+
+	// ResponseType selects who sees the message. Defaults to Ephemeral.
+	ResponseType ResponseType
+
+	// Err is the cause of the error, which is logged but not shown.
+	Err error
+}
+
+// Errorf returns a UserError whose message is formatted from `format` and
+// `args`.
+func Errorf(format string, args ...interface{}) *UserError {
+	return &UserError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Error implements error.
+func (e *UserError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause of the error.
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorRenderer writes the reply to a command whose handler failed.
+//
+// The error is either a *UserError returned by the handler, or an *Incident
+// for any other error or panic, which has already been logged and reported.
+// Errors of interaction handlers are rendered in the same way, with a command
+// which has no Name and describes the user, channel and response_url of the
+// interaction.
+type ErrorRenderer interface {
+	RenderError(w ResponseWriter, cmd *Command, err error)
+}
+
+// ErrorRendererFunc convenience type.
+type ErrorRendererFunc func(w ResponseWriter, cmd *Command, err error)
+
+// RenderError invokes itself.
+func (f ErrorRendererFunc) RenderError(w ResponseWriter, cmd *Command, err error) {
+	f(w, cmd, err)
+}
+
+// RenderError writes the default reply to a command whose handler failed
+// with `err`. A UserError is shown as its message and hint, and an incident
+// as an apology with its ID. Replies are ephemeral unless a UserError selects
+// otherwise.
+func (s *Slacker) RenderError(w ResponseWriter, cmd *Command, err error) {
+	w.SetResponseType(Ephemeral)
+
+	var ue *UserError
+	if errors.As(err, &ue) {
+		if ue.ResponseType != "" {
+			w.SetResponseType(ue.ResponseType)
+		}
+		io.WriteString(w, ue.Message)
+		if ue.Hint != "" {
+			io.WriteString(w, "\n"+ue.Hint)
+		}
+		return
+	}
+
+	var incident *Incident
+	if !errors.As(err, &incident) {
+		incident = &Incident{Err: err}
+	}
+	format := s.ErrorMessage
+	if format == "" {
+		format = DefaultErrorMessage
+	}
+	if incident.Panic != nil {
+		format = s.PanicMessage
+		if format == "" {
+			format = DefaultPanicMessage
+		}
+	}
+	fmt.Fprintf(w, format, incident.ID)
+}
+
+// reply returns the reply to `cmd` for the result of its handler, and logs
+// the outcome. Errors are rendered as replies, and those which are not a
+// UserError are reported as incidents.
+func (s *Slacker) reply(ctx context.Context, cmd *Command, res *result) *Message {
+	msg := res.res.message()
+	err := res.err
+	if err == nil {
+		err = msg.Validate()
+	}
+	if err == nil {
+		cmd.done("ok")
+		return msg
+	}
+
+	var ue *UserError
+	var incident *Incident
+	err = s.incident(ctx, err, &Incident{Command: cmd})
+	if errors.As(err, &ue) {
+		cmd.done("user_error", "err", err)
+	} else if errors.As(err, &incident) {
+		cmd.done("error", "incident", incident.ID)
+	}
+	return s.renderError(cmd, err)
+}
+
+// incident returns `err` if it is a UserError or an Incident. Otherwise
+// `incident` is reported with `err` as its cause, and returned.
+func (s *Slacker) incident(ctx context.Context, err error, incident *Incident) error {
+	var ue *UserError
+	var existing *Incident
+	if errors.As(err, &ue) || errors.As(err, &existing) {
+		return err
+	}
+
+	incident.ID = newID()
+	incident.Err = err
+	s.report(ctx, incident)
+	return incident
+}
+
+// renderError returns the reply to `cmd` for `err`, which is a UserError or
+// an Incident.
+func (s *Slacker) renderError(cmd *Command, err error) *Message {
+	rw := &response{}
+	if s.ErrorRenderer != nil {
+		s.ErrorRenderer.RenderError(rw, cmd, err)
+	} else {
+		s.RenderError(rw, cmd, err)
+	}
+	return rw.message()
+}
@@ -0,0 +1,113 @@
+package slacker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func TestRendersUserErrors(t *testing.T) {
+	reported := false
+	slack := slacker.New()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		reported = true
+	})
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		io.WriteString(w, "partial reply")
+		return &slacker.UserError{
+			Message: "api is already being deployed.",
+			Hint:    "Try again in a few minutes.",
+			Err:     errors.New("lock held"),
+		}
+	})
+	slack.HandleFunc("announce", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		err := &slacker.UserError{Message: "Deploys are frozen.", ResponseType: slacker.InChannel}
+		return fmt.Errorf("checking freeze: %w", err)
+	})
+	slack.HandleFunc("scale", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return slacker.Errorf("Cannot scale %s to %d replicas.", "api", 100)
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.Equal(t, "api is already being deployed.\nTry again in a few minutes.", msg.Text)
+
+	values.Set("command", "/announce")
+	msg = postMessage(t, ts.URL, values)
+	assert.Equal(t, slacker.InChannel, msg.ResponseType)
+	assert.Equal(t, "Deploys are frozen.", msg.Text)
+
+	values.Set("command", "/scale")
+	testReply(t, ts.URL, values, "Cannot scale api to 100 replicas.")
+	assert.Equal(t, false, reported)
+}
+
+func TestUserErrorUnwraps(t *testing.T) {
+	cause := errors.New("lock held")
+	err := &slacker.UserError{Message: "Busy.", Err: cause}
+	assert.Equal(t, "Busy.: lock held", err.Error())
+	assert.Equal(t, true, errors.Is(err, cause))
+}
+
+func TestErrorMessage(t *testing.T) {
+	slack := slacker.New()
+	slack.ErrorMessage = "Failed, see %s."
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return errors.New("connection refused")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/boom")
+	values.Add("token", "foo")
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.Equal(t, "Failed, see ", msg.Text[:12])
+	assert.Equal(t, 25, len(msg.Text))
+}
+
+func TestErrorRenderer(t *testing.T) {
+	slack := slacker.New()
+	slack.ErrorRenderer = slacker.ErrorRendererFunc(func(w slacker.ResponseWriter, cmd *slacker.Command, err error) {
+		var incident *slacker.Incident
+		if errors.As(err, &incident) {
+			fmt.Fprintf(w, ":fire: /%s failed (%s)", cmd.Name, incident.ID[:4])
+			return
+		}
+		slack.RenderError(w, cmd, err)
+		w.Message().Text = ":warning: " + w.Message().Text
+	})
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return errors.New("connection refused")
+	})
+	slack.HandleFunc("nope", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return slacker.Errorf("Nope.")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/boom")
+	values.Add("token", "foo")
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, 26, len(msg.Text))
+	assert.Equal(t, ":fire: /boom failed (", msg.Text[:21])
+
+	values.Set("command", "/nope")
+	msg = postMessage(t, ts.URL, values)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.Equal(t, ":warning: Nope.", msg.Text)
+}
@@ -0,0 +1,296 @@
+package slacker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Envelope types of Events API requests.
+const (
+	URLVerification = "url_verification"
+	EventCallback   = "event_callback"
+)
+
+// Event types with typed data.
+const (
+	EventAppMention          = "app_mention"
+	EventMessage             = "message"
+	EventReactionAdded       = "reaction_added"
+	EventReactionRemoved     = "reaction_removed"
+	EventMemberJoinedChannel = "member_joined_channel"
+	EventMemberLeftChannel   = "member_left_channel"
+)
+
+// EventEnvelope is the outer body of an Events API request.
+type EventEnvelope struct {
+	Type      string          `json:"type"`
+	Token     string          `json:"token"`
+	Challenge string          `json:"challenge,omitempty"`
+	TeamID    string          `json:"team_id,omitempty"`
+	APIAppID  string          `json:"api_app_id,omitempty"`
+	EventID   string          `json:"event_id,omitempty"`
+	EventTime int64           `json:"event_time,omitempty"`
+	Event     json.RawMessage `json:"event,omitempty"`
+
+	// Authorizations holds an installation of the app which the event is
+	// visible to.
+	Authorizations []*Authorization `json:"authorizations,omitempty"`
+}
+
+// Authorization is an installation of the app.
+type Authorization struct {
+	EnterpriseID        string `json:"enterprise_id,omitempty"`
+	TeamID              string `json:"team_id"`
+	UserID              string `json:"user_id"`
+	IsBot               bool   `json:"is_bot"`
+	IsEnterpriseInstall bool   `json:"is_enterprise_install"`
+}
+
+// Event delivered to handlers.
+type Event struct {
+	// Type of the event, such as "app_mention".
+	Type string
+
+	// Envelope the event was delivered in.
+	Envelope *EventEnvelope
+
+	// Data is the event decoded into its typed struct, such as
+	// *AppMentionEvent, or the raw JSON for other types of event.
+	Data interface{}
+
+	// RetryNum and RetryReason are set when Slack redelivers an event.
+	RetryNum    int
+	RetryReason string
+}
+
+// AppMentionEvent is sent when the app is mentioned in a channel.
+type AppMentionEvent struct {
+	User     string `json:"user"`
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+	Channel  string `json:"channel"`
+	Team     string `json:"team,omitempty"`
+	EventTS  string `json:"event_ts"`
+}
+
+// MessageEvent is sent for messages in conversations the app is subscribed to.
+type MessageEvent struct {
+	Subtype     string `json:"subtype,omitempty"`
+	User        string `json:"user,omitempty"`
+	BotID       string `json:"bot_id,omitempty"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts,omitempty"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team,omitempty"`
+	EventTS     string `json:"event_ts"`
+}
+
+// ReactionEvent is sent when a reaction is added or removed.
+type ReactionEvent struct {
+	User     string       `json:"user"`
+	Reaction string       `json:"reaction"`
+	ItemUser string       `json:"item_user,omitempty"`
+	Item     ReactionItem `json:"item"`
+	EventTS  string       `json:"event_ts"`
+}
+
+// ReactionItem is the item a reaction was added to or removed from.
+type ReactionItem struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel,omitempty"`
+	TS      string `json:"ts,omitempty"`
+	File    string `json:"file,omitempty"`
+}
+
+// MemberChannelEvent is sent when a user joins or leaves a channel.
+type MemberChannelEvent struct {
+	User        string `json:"user"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team"`
+	Inviter     string `json:"inviter,omitempty"`
+	EventTS     string `json:"event_ts,omitempty"`
+}
+
+// eventData returns a new value to decode events of type `typ` into.
+func eventData(typ string) interface{} {
+	switch typ {
+	case EventAppMention:
+		return &AppMentionEvent{}
+	case EventMessage:
+		return &MessageEvent{}
+	case EventReactionAdded, EventReactionRemoved:
+		return &ReactionEvent{}
+	case EventMemberJoinedChannel, EventMemberLeftChannel:
+		return &MemberChannelEvent{}
+	default:
+		return nil
+	}
+}
+
+// EventHandler interface. Implementations can be registered to handle events.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, ev *Event) error
+}
+
+// EventHandlerFunc convenience type.
+type EventHandlerFunc func(ctx context.Context, ev *Event) error
+
+// HandleEvent invokes itself.
+func (h EventHandlerFunc) HandleEvent(ctx context.Context, ev *Event) error {
+	return h(ctx, ev)
+}
+
+// Events handles requests to the app's Events API request URL, which are
+// verified in the same way as commands, and dispatches events to handlers
+// registered by event type. When verifying tokens, an event's token must match
+// the token of a registered command.
+type Events struct {
+	slacker  *Slacker
+	handlers map[string][]EventHandler // maps an event type to its handlers.
+	sync.Mutex
+}
+
+// newEvents returns events for `s`.
+func newEvents(s *Slacker) *Events {
+	return &Events{
+		slacker:  s,
+		handlers: make(map[string][]EventHandler),
+	}
+}
+
+// Events returns the handler for the app's Events API request URL.
+func (s *Slacker) Events() *Events {
+	return s.events
+}
+
+// HandleEvent registers `handler` for events of `eventType`. Several handlers
+// may be registered for a type, and are invoked in the order they were
+// registered.
+func (e *Events) HandleEvent(eventType string, handler EventHandler) {
+	e.Lock()
+	defer e.Unlock()
+	e.handlers[eventType] = append(e.handlers[eventType], handler)
+}
+
+// HandleEventFunc registers `handler` function for events of `eventType`.
+func (e *Events) HandleEventFunc(eventType string, handler func(context.Context, *Event) error) {
+	e.HandleEvent(eventType, EventHandlerFunc(handler))
+}
+
+// ServeHTTP handles Events API requests. Events are acknowledged before their
+// handlers run in the background. Shutdown cancels the handlers' context and
+// waits for them to finish.
+func (e *Events) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := e.slacker
+
+	err := s.verifySignature(r)
+	if err != nil {
+		s.logger().Error("verifying signature", "err", err)
+		http.Error(w, "Invalid signature", 401)
+		return
+	}
+
+	env := &EventEnvelope{}
+	err = json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(env)
+	if err != nil {
+		s.logger().Error("parsing event", "err", err)
+		http.Error(w, "Invalid request body", 400)
+		return
+	}
+
+	if s.Verify.checksToken() && !s.validAppToken(env.Token) {
+		s.logger().Error("invalid token for event", "event_id", env.EventID)
+		http.Error(w, "Invalid token", 401)
+		return
+	}
+
+	switch env.Type {
+	case URLVerification:
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, env.Challenge)
+
+	case EventCallback:
+		// Events are acknowledged before they are handled, since Slack
+		// redelivers events which are not acknowledged within 3 seconds.
+		retryNum, _ := strconv.Atoi(r.Header.Get("X-Slack-Retry-Num"))
+		retryReason := r.Header.Get("X-Slack-Retry-Reason")
+		err = s.background(context.WithoutCancel(r.Context()), func(ctx context.Context) {
+			e.handle(ctx, env, retryNum, retryReason)
+		})
+		if err != nil {
+			http.Error(w, "Shutting down", 503)
+			return
+		}
+
+	default:
+		s.logger().Error("unsupported event envelope", "type", env.Type)
+	}
+}
+
+// handle decodes the event in `env` and dispatches it to its handlers. Errors
+// from handlers are logged rather than returned, since Slack would redeliver
+// the event to every handler.
+func (e *Events) handle(ctx context.Context, env *EventEnvelope, retryNum int, retryReason string) {
+	log := e.slacker.logger().With("event_id", env.EventID)
+
+	var head struct {
+		Type string `json:"type"`
+	}
+	err := json.Unmarshal(env.Event, &head)
+	if err != nil {
+		log.Error("parsing event", "err", err)
+		return
+	}
+
+	ev := &Event{
+		Type:        head.Type,
+		Envelope:    env,
+		Data:        env.Event,
+		RetryNum:    retryNum,
+		RetryReason: retryReason,
+	}
+	if data := eventData(head.Type); data != nil {
+		err = json.Unmarshal(env.Event, data)
+		if err != nil {
+			log.Error("parsing event", "type", head.Type, "err", err)
+			return
+		}
+		ev.Data = data
+	}
+
+	e.Lock()
+	handlers := e.handlers[ev.Type]
+	e.Unlock()
+
+	if len(handlers) == 0 {
+		log.Error("no handler for event", "type", ev.Type)
+		return
+	}
+
+	for _, h := range handlers {
+		err := e.invoke(ctx, h, ev)
+		if err != nil {
+			log.Error("handling event", "type", ev.Type, "err", err)
+		}
+	}
+}
+
+// invoke calls `h` for `ev`. A panic is recovered, since handlers run in the
+// background, and reported as an incident rather than returned.
+func (e *Events) invoke(ctx context.Context, h EventHandler, ev *Event) error {
+	defer func() {
+		v := recover()
+		if v != nil {
+			e.slacker.panicked(ctx, &Incident{Event: ev}, "event "+ev.Type, v)
+		}
+	}()
+	return h.HandleEvent(ctx, ev)
+}
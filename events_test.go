@@ -0,0 +1,182 @@
+package slacker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// Post an Events API `body` to the given url, signed with `secret`.
+func postEvent(t *testing.T, url, secret string, body interface{}) *http.Response {
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("could not marshal event with error: %s", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("could not create request with error: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slacker.Sign(secret, timestamp, b))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	return res
+}
+
+func newEventSlacker() *slacker.Slacker {
+	slack := slacker.New()
+	slack.Verify = slacker.VerifySignature
+	slack.SigningSecrets = []string{"secret"}
+	return slack
+}
+
+func TestURLVerification(t *testing.T) {
+	slack := newEventSlacker()
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+		"type":      "url_verification",
+		"challenge": "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P",
+	})
+	assert.Equal(t, 200, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P", string(body))
+
+	res = postEvent(t, ts.URL, "wrong", map[string]interface{}{"type": "url_verification"})
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestDispatchesEvents(t *testing.T) {
+	events := make(chan *slacker.Event, 4)
+	slack := newEventSlacker()
+	record := func(ctx context.Context, ev *slacker.Event) error {
+		events <- ev
+		return nil
+	}
+	slack.Events().HandleEventFunc(slacker.EventAppMention, record)
+	slack.Events().HandleEventFunc(slacker.EventReactionAdded, record)
+	slack.Events().HandleEventFunc("team_join", record)
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	post := func(event map[string]interface{}) {
+		res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+			"type":     "event_callback",
+			"team_id":  "T123",
+			"event_id": "Ev123",
+			"event":    event,
+		})
+		assert.Equal(t, 200, res.StatusCode)
+	}
+
+	post(map[string]interface{}{"type": "app_mention", "user": "U123", "text": "<@U456> deploy api", "channel": "C123", "ts": "1.2"})
+	ev := <-events
+	assert.Equal(t, "app_mention", ev.Type)
+	assert.Equal(t, "T123", ev.Envelope.TeamID)
+	assert.Equal(t, &slacker.AppMentionEvent{User: "U123", Text: "<@U456> deploy api", Channel: "C123", TS: "1.2"}, ev.Data)
+
+	post(map[string]interface{}{"type": "reaction_added", "user": "U123", "reaction": "tada", "item": map[string]interface{}{"type": "message", "channel": "C123", "ts": "1.2"}})
+	reaction := (<-events).Data.(*slacker.ReactionEvent)
+	assert.Equal(t, "tada", reaction.Reaction)
+	assert.Equal(t, "1.2", reaction.Item.TS)
+
+	post(map[string]interface{}{"type": "team_join"})
+	assert.Equal(t, json.RawMessage(`{"type":"team_join"}`), (<-events).Data)
+
+	post(map[string]interface{}{"type": "channel_created"})
+	slack.Shutdown(context.Background())
+	assert.Equal(t, 0, len(events))
+}
+
+func TestAcknowledgesEventsBeforeHandling(t *testing.T) {
+	release := make(chan struct{})
+	handled := make(chan struct{})
+	slack := newEventSlacker()
+	slack.Events().HandleEventFunc("team_join", func(ctx context.Context, ev *slacker.Event) error {
+		<-release
+		close(handled)
+		return nil
+	})
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+		"type":  "event_callback",
+		"event": map[string]interface{}{"type": "team_join"},
+	})
+	assert.Equal(t, 200, res.StatusCode)
+
+	close(release)
+	<-handled
+}
+
+func TestRecoversPanicsInEventHandlers(t *testing.T) {
+	incidents := make(chan *slacker.Incident, 1)
+	handled := make(chan struct{})
+	slack := newEventSlacker()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		incidents <- incident
+	})
+	slack.Events().HandleEventFunc("team_join", func(ctx context.Context, ev *slacker.Event) error {
+		panic("boom")
+	})
+	slack.Events().HandleEventFunc("team_join", func(ctx context.Context, ev *slacker.Event) error {
+		close(handled)
+		return nil
+	})
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+		"type":     "event_callback",
+		"event_id": "Ev1",
+		"event":    map[string]interface{}{"type": "team_join"},
+	})
+	assert.Equal(t, 200, res.StatusCode)
+
+	incident := <-incidents
+	assert.Equal(t, "team_join", incident.Event.Type)
+	assert.Equal(t, "Ev1", incident.Event.Envelope.EventID)
+	assert.Equal(t, "boom", incident.Panic)
+	assert.Equal(t, "panic handling event team_join: boom", incident.Err.Error())
+	<-handled
+}
+
+func TestShutdownCancelsEventHandlers(t *testing.T) {
+	started := make(chan struct{})
+	slack := newEventSlacker()
+	slack.Events().HandleEventFunc("team_join", func(ctx context.Context, ev *slacker.Event) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ts := httptest.NewServer(slack.Events())
+	defer ts.Close()
+
+	res := postEvent(t, ts.URL, "secret", map[string]interface{}{
+		"type":  "event_callback",
+		"event": map[string]interface{}{"type": "team_join"},
+	})
+	assert.Equal(t, 200, res.StatusCode)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Equal(t, nil, slack.Shutdown(ctx))
+}
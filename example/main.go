@@ -6,8 +6,8 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/docopt/docopt-go"
 	"github.com/segmentio/go-slacker"
-	"github.com/tj/docopt"
 )
 
 const (
@@ -27,7 +27,7 @@ const (
 )
 
 func main() {
-	args, err := docopt.Parse(usage, nil, true, version, false)
+	args, err := docopt.ParseArgs(usage, nil, version)
 	if err != nil {
 		log.Fatalf("error: %s", err)
 	}
@@ -49,6 +49,7 @@ func main() {
 	})
 
 	slack.HandleFunc("deploy", token, func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).SetResponseType(slacker.InChannel)
 		fmt.Fprintf(w, "Deploying %q", cmd.Text)
 		return nil
 	})
@@ -0,0 +1,134 @@
+// Package format builds Slack mrkdwn for the text of replies.
+//
+// Text from users must be escaped before it is written to a reply, since
+// Slack treats "<" as the start of a mention or link and "&" as the start of
+// an entity, which would otherwise let it inject mentions such as
+// "<!channel>".
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Special mentions.
+const (
+	Here     = "<!here>"
+	Everyone = "<!everyone>"
+
+	// ChannelMention notifies every member of the channel.
+	ChannelMention = "<!channel>"
+)
+
+// Tokens of date formats, for Date.
+const (
+	DateNum          = "{date_num}"
+	DateShort        = "{date_short}"
+	DateLong         = "{date_long}"
+	DatePretty       = "{date_pretty}"
+	DateShortPretty  = "{date_short_pretty}"
+	DateLongPretty   = "{date_long_pretty}"
+	Time             = "{time}"
+	TimeSecs         = "{time_secs}"
+	DateSlackPrefers = "{date_short_pretty} at {time}"
+)
+
+// escaper escapes the characters Slack reserves.
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markup escapes the characters Slack reserves, and breaks up the characters
+// which mark formatting with a zero width space.
+var markup = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"*", "*\u200b",
+	"_", "_\u200b",
+	"~", "~\u200b",
+	"`", "`\u200b",
+)
+
+// Escape escapes the characters Slack reserves in `s`, so that it is shown as
+// written rather than as mentions or links.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// EscapeMarkup escapes `s` like Escape, and also stops the characters which
+// mark bold, italic, strikethrough and code from formatting it.
+func EscapeMarkup(s string) string {
+	return markup.Replace(s)
+}
+
+// User returns a mention of user `id`.
+func User(id string) string {
+	return "<@" + id + ">"
+}
+
+// Channel returns a reference to channel `id`.
+func Channel(id string) string {
+	return "<#" + id + ">"
+}
+
+// Usergroup returns a mention of usergroup `id`.
+func Usergroup(id string) string {
+	return "<!subteam^" + id + ">"
+}
+
+// Link returns a link to `url` labelled with `label`, or showing the URL if
+// `label` is empty.
+func Link(url, label string) string {
+	if label == "" {
+		return "<" + Escape(url) + ">"
+	}
+	return "<" + Escape(url) + "|" + Escape(label) + ">"
+}
+
+// Date returns `t` shown in the reader's timezone using `format`, which
+// contains tokens such as DateShort, or shown as `fallback` by clients which
+// cannot format dates.
+func Date(t time.Time, format, fallback string) string {
+	return fmt.Sprintf("<!date^%d^%s|%s>", t.Unix(), format, Escape(fallback))
+}
+
+// DateLink returns `t` formatted like Date, linking to `url`.
+func DateLink(t time.Time, format, url, fallback string) string {
+	return fmt.Sprintf("<!date^%d^%s^%s|%s>", t.Unix(), format, Escape(url), Escape(fallback))
+}
+
+// Bold returns mrkdwn `s` in bold.
+func Bold(s string) string {
+	return "*" + s + "*"
+}
+
+// Italic returns mrkdwn `s` in italics.
+func Italic(s string) string {
+	return "_" + s + "_"
+}
+
+// Strike returns mrkdwn `s` struck through.
+func Strike(s string) string {
+	return "~" + s + "~"
+}
+
+// Code returns `s` as inline code. Slack has no way to escape backticks in
+// code, so they are replaced with a lookalike.
+func Code(s string) string {
+	return "`" + strings.ReplaceAll(Escape(s), "`", "ˋ") + "`"
+}
+
+// CodeBlock returns `s` as a block of preformatted text.
+func CodeBlock(s string) string {
+	s = strings.ReplaceAll(Escape(s), "```", "`\u200b``")
+	return "```\n" + strings.Trim(s, "\n") + "\n```"
+}
+
+// Quote returns mrkdwn `s` as a block quote.
+func Quote(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ">" + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,67 @@
+package format_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker/format"
+)
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "a &amp; b &lt;!channel&gt; *c*", format.Escape("a & b <!channel> *c*"))
+	assert.Equal(t, "a &amp; &lt;b&gt; *\u200bc*\u200b _\u200bd_\u200b ~\u200be~\u200b `\u200bf`\u200b", format.EscapeMarkup("a & <b> *c* _d_ ~e~ `f`"))
+}
+
+func TestMentions(t *testing.T) {
+	assert.Equal(t, "<@U123>", format.User("U123"))
+	assert.Equal(t, "<#C123>", format.Channel("C123"))
+	assert.Equal(t, "<!subteam^S123>", format.Usergroup("S123"))
+	assert.Equal(t, "<!here>", format.Here)
+}
+
+func TestLink(t *testing.T) {
+	assert.Equal(t, "<https://x.com/?a=1&amp;b=2|runbook &lt;v2&gt;>", format.Link("https://x.com/?a=1&b=2", "runbook <v2>"))
+	assert.Equal(t, "<https://x.com>", format.Link("https://x.com", ""))
+}
+
+func TestDate(t *testing.T) {
+	at := time.Unix(1392734382, 0)
+	assert.Equal(t, "<!date^1392734382^{date_short} at {time}|Feb 18 &amp; later>", format.Date(at, format.DateShort+" at "+format.Time, "Feb 18 & later"))
+	assert.Equal(t, "<!date^1392734382^{date_num}^https://x.com|2014-02-18>", format.DateLink(at, format.DateNum, "https://x.com", "2014-02-18"))
+}
+
+func TestStyles(t *testing.T) {
+	assert.Equal(t, "*bold*", format.Bold("bold"))
+	assert.Equal(t, "_italic_", format.Italic("italic"))
+	assert.Equal(t, "~strike~", format.Strike("strike"))
+	assert.Equal(t, "`a &lt; bˋc`", format.Code("a < b`c"))
+	assert.Equal(t, "```\nif a &lt; b {\n}\n`\u200b``\n```", format.CodeBlock("\nif a < b {\n}\n```\n"))
+	assert.Equal(t, ">one\n>\n>two", format.Quote("one\n\ntwo\n"))
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		md       string
+		expected string
+	}{
+		{"**bold** and __bold__", "*bold* and *bold*"},
+		{"*italic* and _italic_", "_italic_ and _italic_"},
+		{"~~gone~~", "~gone~"},
+		{"***both***", "*_both_*"},
+		{"snake_case_name and 2 * 3 * 4", "snake_case_name and 2 * 3 * 4"},
+		{"see [the **docs**](https://x.com/?a=1&b=2) or ![logo](https://x.com/l.png)", "see <https://x.com/?a=1&amp;b=2|the *docs*> or <https://x.com/l.png|logo>"},
+		{"visit <https://x.com> or a <b> tag & more", "visit <https://x.com> or a &lt;b&gt; tag &amp; more"},
+		{"use `a < *b*` here", "use `a &lt; *b*` here"},
+		{"not \\*emphasis\\*", "not *\u200bemphasis*\u200b"},
+		{"# Title\n## Sub *title* ##", "*Title*\n*Sub _title_*"},
+		{"- one\n* two\n  + nested\n1. first", "• one\n• two\n  • nested\n1. first"},
+		{"> quoted **text**", ">quoted *text*"},
+		{"---", "──────────"},
+		{"```go\nif a < b {\n\t**x**\n}\n```\nafter", "```\nif a &lt; b {\n\t**x**\n}\n```\nafter"},
+		{"~~~\nunclosed", "```\nunclosed\n```"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, format.Markdown(test.md))
+	}
+}
@@ -0,0 +1,180 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fence     = regexp.MustCompile("^\\s*(```|~~~)")
+	heading   = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	bullet    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	quoted    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	rule      = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	autolink  = regexp.MustCompile(`^<((?:https?|mailto):[^<>\s]+)>`)
+	linkLabel = regexp.MustCompile(`^!?\[([^\]]*)\]\(([^()\s]+)(?:\s+"[^"]*")?\)`)
+)
+
+// Markdown converts CommonMark or GitHub flavored markdown `md` into Slack
+// mrkdwn. Emphasis, strikethrough, links, images, headings, lists, quotes and
+// code are converted; the rest is escaped and passed through.
+func Markdown(md string) string {
+	var out []string
+	var code string // the fence of the code block being read, if any.
+
+	for _, line := range strings.Split(md, "\n") {
+		if code != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), code) {
+				out = append(out, "```")
+				code = ""
+				continue
+			}
+			out = append(out, strings.ReplaceAll(Escape(line), "```", "`\u200b``"))
+			continue
+		}
+
+		if m := fence.FindStringSubmatch(line); m != nil {
+			code = m[1]
+			out = append(out, "```")
+			continue
+		}
+
+		switch {
+		case rule.MatchString(line):
+			out = append(out, "──────────")
+		case heading.MatchString(line):
+			text := heading.FindStringSubmatch(line)[1]
+			out = append(out, Bold(inline(text)))
+		case bullet.MatchString(line):
+			m := bullet.FindStringSubmatch(line)
+			out = append(out, m[1]+"• "+inline(m[2]))
+		case quoted.MatchString(line):
+			out = append(out, ">"+inline(quoted.FindStringSubmatch(line)[1]))
+		default:
+			out = append(out, inline(line))
+		}
+	}
+
+	// Close a code block left open.
+	if code != "" {
+		out = append(out, "```")
+	}
+	return strings.Join(out, "\n")
+}
+
+// inline converts the inline markdown of a line.
+func inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && isPunct(rest[1]):
+			b.WriteString(EscapeMarkup(rest[1:2]))
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			n := 1
+			for n < len(rest) && rest[n] == '`' {
+				n++
+			}
+			if end := strings.Index(rest[n:], rest[:n]); end >= 0 {
+				b.WriteString(Code(strings.TrimSpace(rest[n : n+end])))
+				i += n + end + n
+				continue
+			}
+
+		case rest[0] == '[' || strings.HasPrefix(rest, "!["):
+			if m := linkLabel.FindStringSubmatch(rest); m != nil {
+				label := inline(m[1])
+				if label == "" {
+					b.WriteString("<" + Escape(m[2]) + ">")
+				} else {
+					b.WriteString("<" + Escape(m[2]) + "|" + label + ">")
+				}
+				i += len(m[0])
+				continue
+			}
+
+		case rest[0] == '<':
+			if m := autolink.FindStringSubmatch(rest); m != nil {
+				b.WriteString(Link(m[1], ""))
+				i += len(m[0])
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if inner, n := emphasis(s, i, rest[:2]); n > 0 {
+				b.WriteString(Bold(inline(inner)))
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if inner, n := emphasis(s, i, "~~"); n > 0 {
+				b.WriteString(Strike(inline(inner)))
+				i += n
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if inner, n := emphasis(s, i, rest[:1]); n > 0 {
+				b.WriteString(Italic(inline(inner)))
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(Escape(rest[:1]))
+		i++
+	}
+	return b.String()
+}
+
+// emphasis returns the text emphasized by `delim` at offset `i` of `s`, and
+// the length of the emphasis including its delimiters, or 0 if the delimiter
+// does not open emphasis. Like CommonMark, underscores within words do not.
+func emphasis(s string, i int, delim string) (string, int) {
+	if delim[0] == '_' && i > 0 && isWordChar(s[i-1]) {
+		return "", 0
+	}
+
+	start := i + len(delim)
+	if start >= len(s) || s[start] == ' ' {
+		return "", 0
+	}
+
+	for j := start + 1; j+len(delim) <= len(s); j++ {
+		if s[j:j+len(delim)] != delim || s[j-1] == ' ' {
+			continue
+		}
+		// A single delimiter must not be part of a double one.
+		if len(delim) == 1 && j+1 < len(s) && s[j+1] == delim[0] {
+			j++
+			continue
+		}
+		end := j + len(delim)
+		// A double delimiter closes at the end of a run, so that "***a***"
+		// is bold and italic.
+		if len(delim) == 2 && end < len(s) && s[end] == delim[0] {
+			continue
+		}
+		if delim[0] == '_' && end < len(s) && isWordChar(s[end]) {
+			continue
+		}
+		return s[start:j], end - i
+	}
+	return "", 0
+}
+
+// isWordChar reports whether `c` is an ASCII letter or digit.
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isPunct reports whether `c` is ASCII punctuation, which markdown allows to
+// be escaped.
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
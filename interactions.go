@@ -0,0 +1,459 @@
+package slacker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/segmentio/go-slacker/blocks"
+)
+
+// Interaction types.
+const (
+	BlockActions   = "block_actions"
+	ViewSubmission = "view_submission"
+	ViewClosed     = "view_closed"
+)
+
+// Interaction details sent by Slack when a user interacts with a block
+// element or a modal.
+type Interaction struct {
+	Type        string     `json:"type"`
+	Token       string     `json:"token"`
+	TriggerID   string     `json:"trigger_id"`
+	ResponseURL string     `json:"response_url"`
+	APIAppID    string     `json:"api_app_id"`
+	Team        Team       `json:"team"`
+	User        User       `json:"user"`
+	Channel     *Channel   `json:"channel,omitempty"`
+	Container   *Container `json:"container,omitempty"`
+	Actions     []*Action  `json:"actions,omitempty"`
+	View        *View      `json:"view,omitempty"`
+	IsCleared   bool       `json:"is_cleared,omitempty"`
+
+	// Message is the message containing the element, for block_actions from
+	// messages.
+	Message json.RawMessage `json:"message,omitempty"`
+
+	// Action is the action being handled, for block_actions.
+	Action *Action `json:"-"`
+
+	responder    *Responder
+	viewResponse *ViewResponse
+}
+
+// Responder returns the responder for the interaction's response_url.
+func (in *Interaction) Responder() *Responder {
+	if in.responder == nil {
+		return NewResponder(in.ResponseURL, nil)
+	}
+	return in.responder
+}
+
+// Followup posts `msg` to the interaction's response_url.
+func (in *Interaction) Followup(ctx context.Context, msg *Message) error {
+	return in.Responder().Post(ctx, msg)
+}
+
+// SetViewResponse sets the reply to a view_submission, which can update,
+// push or clear views, or show errors.
+func (in *Interaction) SetViewResponse(r *ViewResponse) {
+	in.viewResponse = r
+}
+
+// Team which an interaction came from.
+type Team struct {
+	ID     string `json:"id"`
+	Domain string `json:"domain"`
+}
+
+// User who interacted.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	TeamID   string `json:"team_id"`
+}
+
+// Channel an interaction happened in.
+type Channel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Container of the element which was interacted with.
+type Container struct {
+	Type        string `json:"type"`
+	MessageTS   string `json:"message_ts,omitempty"`
+	ChannelID   string `json:"channel_id,omitempty"`
+	IsEphemeral bool   `json:"is_ephemeral,omitempty"`
+	ViewID      string `json:"view_id,omitempty"`
+}
+
+// Action taken on a block element. It also holds the state of an input in a
+// submitted view.
+type Action struct {
+	Type                 string           `json:"type"`
+	ActionID             string           `json:"action_id"`
+	BlockID              string           `json:"block_id,omitempty"`
+	ActionTS             string           `json:"action_ts,omitempty"`
+	Text                 *blocks.Text     `json:"text,omitempty"`
+	Value                string           `json:"value,omitempty"`
+	SelectedOption       *blocks.Option   `json:"selected_option,omitempty"`
+	SelectedOptions      []*blocks.Option `json:"selected_options,omitempty"`
+	SelectedDate         string           `json:"selected_date,omitempty"`
+	SelectedUser         string           `json:"selected_user,omitempty"`
+	SelectedChannel      string           `json:"selected_channel,omitempty"`
+	SelectedConversation string           `json:"selected_conversation,omitempty"`
+}
+
+// View is a modal or app home view.
+type View struct {
+	ID              string     `json:"id"`
+	TeamID          string     `json:"team_id"`
+	Type            string     `json:"type"`
+	CallbackID      string     `json:"callback_id"`
+	PrivateMetadata string     `json:"private_metadata"`
+	ExternalID      string     `json:"external_id"`
+	Hash            string     `json:"hash"`
+	RootViewID      string     `json:"root_view_id"`
+	PreviousViewID  string     `json:"previous_view_id"`
+	State           *ViewState `json:"state,omitempty"`
+}
+
+// ViewState holds the values of a view's inputs, mapped by block_id and then
+// action_id.
+type ViewState struct {
+	Values map[string]map[string]*Action `json:"values"`
+}
+
+// Value returns the input with `actionID` in block `blockID`, or nil.
+func (s *ViewState) Value(blockID, actionID string) *Action {
+	if s == nil {
+		return nil
+	}
+	return s.Values[blockID][actionID]
+}
+
+// ViewResponse is the reply to a view_submission.
+type ViewResponse struct {
+	ResponseAction string            `json:"response_action"` // update, push, clear or errors.
+	View           interface{}       `json:"view,omitempty"`
+	Errors         map[string]string `json:"errors,omitempty"`
+}
+
+// ViewErrors may be returned by handlers of view_submissions to show errors
+// on the view's inputs. It maps a block_id to the error shown beside it.
+type ViewErrors map[string]string
+
+// Error implements error.
+func (e ViewErrors) Error() string {
+	return "slacker: invalid view submission"
+}
+
+// InteractionHandler interface. Implementations can be registered to handle
+// interactions.
+//
+// Handlers of block_actions may write a message to `w`, which is posted to the
+// interaction's response_url. Handlers of view_submissions may return
+// ViewErrors or set a ViewResponse.
+type InteractionHandler interface {
+	HandleInteraction(ctx context.Context, w ResponseWriter, in *Interaction) error
+}
+
+// InteractionHandlerFunc convenience type.
+type InteractionHandlerFunc func(ctx context.Context, w ResponseWriter, in *Interaction) error
+
+// HandleInteraction invokes itself.
+func (h InteractionHandlerFunc) HandleInteraction(ctx context.Context, w ResponseWriter, in *Interaction) error {
+	return h(ctx, w, in)
+}
+
+// Interactions handles requests to the app's interactivity URL, which are
+// verified in the same way as commands, and dispatches them to registered
+// handlers. When verifying tokens, an interaction's token must match the token
+// of a registered command.
+type Interactions struct {
+	// Callbacks stores callbacks registered with Callback. Defaults to a
+	// MemoryStore.
+	Callbacks CallbackStore
+
+	// ExpiredMessage replies to actions whose callback has expired. Defaults to
+	// DefaultExpiredMessage.
+	ExpiredMessage string
+
+	slacker *Slacker
+	actions map[string]InteractionHandler // maps an action_id to its handler.
+	blocks  map[string]InteractionHandler // maps a block_id to its handler.
+	views   map[string]InteractionHandler // maps a callback_id to its handler.
+	sync.Mutex
+}
+
+// newInteractions returns interactions for `s`.
+func newInteractions(s *Slacker) *Interactions {
+	return &Interactions{
+		Callbacks: NewMemoryStore(),
+		slacker:   s,
+		actions:   make(map[string]InteractionHandler),
+		blocks:    make(map[string]InteractionHandler),
+		views:     make(map[string]InteractionHandler),
+	}
+}
+
+// Interactions returns the handler for the app's interactivity URL.
+func (s *Slacker) Interactions() *Interactions {
+	return s.interactions
+}
+
+// HandleAction registers `handler` for actions on elements with `actionID`.
+func (i *Interactions) HandleAction(actionID string, handler InteractionHandler) {
+	i.Lock()
+	defer i.Unlock()
+	i.actions[actionID] = handler
+}
+
+// HandleActionFunc registers `handler` function for actions on elements with
+// `actionID`.
+func (i *Interactions) HandleActionFunc(actionID string, handler func(context.Context, ResponseWriter, *Interaction) error) {
+	i.HandleAction(actionID, InteractionHandlerFunc(handler))
+}
+
+// HandleBlock registers `handler` for actions on elements in blocks with
+// `blockID`. Handlers registered by action_id take precedence.
+func (i *Interactions) HandleBlock(blockID string, handler InteractionHandler) {
+	i.Lock()
+	defer i.Unlock()
+	i.blocks[blockID] = handler
+}
+
+// HandleBlockFunc registers `handler` function for actions on elements in
+// blocks with `blockID`.
+func (i *Interactions) HandleBlockFunc(blockID string, handler func(context.Context, ResponseWriter, *Interaction) error) {
+	i.HandleBlock(blockID, InteractionHandlerFunc(handler))
+}
+
+// HandleView registers `handler` for submissions and closures of views with
+// `callbackID`.
+func (i *Interactions) HandleView(callbackID string, handler InteractionHandler) {
+	i.Lock()
+	defer i.Unlock()
+	i.views[callbackID] = handler
+}
+
+// HandleViewFunc registers `handler` function for submissions and closures of
+// views with `callbackID`.
+func (i *Interactions) HandleViewFunc(callbackID string, handler func(context.Context, ResponseWriter, *Interaction) error) {
+	i.HandleView(callbackID, InteractionHandlerFunc(handler))
+}
+
+// ServeHTTP handles interaction requests.
+func (i *Interactions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := i.slacker
+
+	err := s.verifySignature(r)
+	if err != nil {
+		s.logger().Error("verifying signature", "err", err)
+		http.Error(w, "Invalid signature", 401)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		s.logger().Error("parsing form", "err", err)
+		http.Error(w, "Invalid request body", 400)
+		return
+	}
+
+	payload := r.PostForm.Get("payload")
+	if payload == "" {
+		http.Error(w, "payload required", 400)
+		return
+	}
+
+	in := &Interaction{}
+	err = json.Unmarshal([]byte(payload), in)
+	if err != nil {
+		s.logger().Error("parsing payload", "err", err)
+		http.Error(w, "Invalid payload", 400)
+		return
+	}
+
+	if s.Verify.checksToken() && !s.validAppToken(in.Token) {
+		s.logger().Error("invalid token for interaction", "type", in.Type)
+		http.Error(w, "Invalid token", 401)
+		return
+	}
+
+	// Actions are acknowledged before they are handled, since their handlers
+	// reply to the response_url, which may take longer than the 3 seconds
+	// Slack waits for.
+	if in.Type == BlockActions {
+		err = s.background(context.WithoutCancel(r.Context()), func(ctx context.Context) {
+			i.serve(ctx, in)
+		})
+		if err != nil {
+			http.Error(w, "Shutting down", 503)
+		}
+		return
+	}
+
+	reply := i.serve(r.Context(), in)
+	if reply != nil {
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(reply)
+		if err != nil {
+			s.logger().Error("writing reply", "err", err)
+		}
+	}
+}
+
+// serve dispatches `in` to its handlers, and returns the reply to send in
+// response to the request, if any. Errors are reported and replied to with
+// fail.
+func (i *Interactions) serve(ctx context.Context, in *Interaction) *ViewResponse {
+	reply, err := i.handle(ctx, in)
+	if err != nil {
+		i.fail(ctx, in, err)
+		return nil
+	}
+	return reply
+}
+
+// handle dispatches `in` to its handlers, and returns the reply to send in
+// response to the request, if any.
+func (i *Interactions) handle(ctx context.Context, in *Interaction) (*ViewResponse, error) {
+	in.responder = NewResponder(in.ResponseURL, i.slacker.Client)
+
+	switch in.Type {
+	case BlockActions:
+		for _, action := range in.Actions {
+			h := i.action(action)
+			if h == nil {
+				i.slacker.logger().Error("no handler for action", "action_id", action.ActionID, "block_id", action.BlockID)
+				continue
+			}
+
+			a := *in
+			a.Action = action
+			err := i.invoke(ctx, h, &a)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+
+	case ViewSubmission, ViewClosed:
+		if in.View == nil {
+			return nil, nil
+		}
+		i.Lock()
+		h, ok := i.views[in.View.CallbackID]
+		i.Unlock()
+		if !ok {
+			i.slacker.logger().Error("no handler for view", "callback_id", in.View.CallbackID)
+			return nil, nil
+		}
+
+		err := i.invoke(ctx, h, in)
+		if errs, ok := err.(ViewErrors); ok && in.Type == ViewSubmission {
+			return &ViewResponse{ResponseAction: "errors", Errors: errs}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return in.viewResponse, nil
+
+	default:
+		i.slacker.logger().Error("unsupported interaction", "type", in.Type)
+		return nil, nil
+	}
+}
+
+// fail reports the error of a handler of `in` as commands' errors are, and
+// posts the reply rendered for it to the interaction's response_url, if any.
+// Interactions without a response_url, such as view submissions, are only
+// reported, since Slack shows no reply to them.
+func (i *Interactions) fail(ctx context.Context, in *Interaction, err error) {
+	s := i.slacker
+	ctx = context.WithoutCancel(ctx)
+
+	err = s.incident(ctx, err, &Incident{Interaction: in})
+	var ue *UserError
+	if errors.As(err, &ue) {
+		s.logger().Info("interaction failed", "type", in.Type, "err", err)
+	}
+	if in.ResponseURL == "" {
+		return
+	}
+
+	msg := s.renderError(in.command(), err)
+	if msg.empty() {
+		return
+	}
+	err = in.Followup(ctx, msg)
+	if err != nil {
+		s.logger().Error("posting reply", "type", in.Type, "err", err)
+	}
+}
+
+// command returns a command describing the user, channel and response_url of
+// the interaction, with which its errors are rendered.
+func (in *Interaction) command() *Command {
+	cmd := &Command{
+		Token:       in.Token,
+		UserID:      in.User.ID,
+		UserName:    in.User.Username,
+		ResponseURL: in.ResponseURL,
+		TeamID:      in.Team.ID,
+		TeamDomain:  in.Team.Domain,
+		TriggerID:   in.TriggerID,
+		APIAppID:    in.APIAppID,
+		responder:   in.responder,
+	}
+	if in.Channel != nil {
+		cmd.ChannelID = in.Channel.ID
+		cmd.ChannelName = in.Channel.Name
+	}
+	return cmd
+}
+
+// action returns the handler for `action`, or nil.
+func (i *Interactions) action(action *Action) InteractionHandler {
+	if isCallback(action.ActionID) {
+		return i.callback(action.ActionID)
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	if h, ok := i.actions[action.ActionID]; ok {
+		return h
+	}
+	return i.blocks[action.BlockID]
+}
+
+// invoke calls `h` for `in`, and posts the message it writes to the
+// response_url. A panic is recovered and returned as an incident, since
+// actions are handled in the background.
+func (i *Interactions) invoke(ctx context.Context, h InteractionHandler, in *Interaction) (err error) {
+	defer func() {
+		v := recover()
+		if v != nil {
+			err = i.slacker.panicked(ctx, &Incident{Interaction: in}, "interaction "+in.Type, v)
+		}
+	}()
+
+	var res response
+	err = h.HandleInteraction(ctx, &res, in)
+	if err != nil {
+		return err
+	}
+
+	msg := res.message()
+	if msg.empty() {
+		return nil
+	}
+	return in.Followup(ctx, msg)
+}
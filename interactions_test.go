@@ -0,0 +1,220 @@
+package slacker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// Post an interaction `payload` to the given url.
+func postInteraction(t *testing.T, url string, payload interface{}) *http.Response {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("could not marshal payload with error: %s", err)
+	}
+	values := make(map[string][]string)
+	values["payload"] = []string{string(b)}
+	res, err := http.PostForm(url, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	return res
+}
+
+func newInteractiveSlacker() *slacker.Slacker {
+	slack := slacker.New()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return nil
+	})
+	return slack
+}
+
+func TestDispatchesActions(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	slack := newInteractiveSlacker()
+	interactions := slack.Interactions()
+	interactions.HandleActionFunc("rollback", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		w.Message().ReplaceOriginal = true
+		fmt.Fprintf(w, "Rolling back %s for %s", in.Action.Value, in.User.Name)
+		return nil
+	})
+	interactions.HandleBlockFunc("env", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		fmt.Fprintf(w, "Selected %s", in.Action.SelectedOption.Value)
+		return nil
+	})
+	ts := httptest.NewServer(interactions)
+	defer ts.Close()
+
+	res := postInteraction(t, ts.URL, map[string]interface{}{
+		"type":         "block_actions",
+		"token":        "foo",
+		"response_url": hs.URL,
+		"user":         map[string]interface{}{"id": "U123", "name": "alice"},
+		"actions": []interface{}{
+			map[string]interface{}{"type": "button", "action_id": "rollback", "block_id": "deploy", "value": "api"},
+			map[string]interface{}{"type": "static_select", "action_id": "select", "block_id": "env", "selected_option": map[string]interface{}{"value": "staging"}},
+			map[string]interface{}{"type": "button", "action_id": "unknown"},
+		},
+	})
+	assert.Equal(t, 200, res.StatusCode)
+
+	msgs := waitMessages(t, hook, 2)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, &slacker.Message{Text: "Rolling back api for alice", ReplaceOriginal: true}, msgs[0])
+	assert.Equal(t, &slacker.Message{Text: "Selected staging"}, msgs[1])
+}
+
+func TestDispatchesViews(t *testing.T) {
+	slack := newInteractiveSlacker()
+	interactions := slack.Interactions()
+	interactions.HandleViewFunc("deploy", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		app := in.View.State.Value("app", "app_input")
+		if app == nil || app.Value == "" {
+			return slacker.ViewErrors{"app": "App is required"}
+		}
+		in.SetViewResponse(&slacker.ViewResponse{ResponseAction: "clear"})
+		return nil
+	})
+	ts := httptest.NewServer(interactions)
+	defer ts.Close()
+
+	submit := func(value string) *slacker.ViewResponse {
+		res := postInteraction(t, ts.URL, map[string]interface{}{
+			"type":  "view_submission",
+			"token": "foo",
+			"view": map[string]interface{}{
+				"callback_id": "deploy",
+				"state": map[string]interface{}{"values": map[string]interface{}{
+					"app": map[string]interface{}{"app_input": map[string]interface{}{"type": "plain_text_input", "value": value}},
+				}},
+			},
+		})
+		assert.Equal(t, 200, res.StatusCode)
+		var reply slacker.ViewResponse
+		err := json.NewDecoder(res.Body).Decode(&reply)
+		if err != nil {
+			t.Fatalf("could not decode reply with error: %s", err)
+		}
+		return &reply
+	}
+
+	assert.Equal(t, &slacker.ViewResponse{ResponseAction: "errors", Errors: map[string]string{"app": "App is required"}}, submit(""))
+	assert.Equal(t, &slacker.ViewResponse{ResponseAction: "clear"}, submit("api"))
+}
+
+func TestVerifiesInteractions(t *testing.T) {
+	slack := newInteractiveSlacker()
+	ts := httptest.NewServer(slack.Interactions())
+	defer ts.Close()
+
+	res := postInteraction(t, ts.URL, map[string]interface{}{"type": "block_actions", "token": "non-foo"})
+	assert.Equal(t, 401, res.StatusCode)
+
+	values := url.Values{}
+	testResponse(t, ts.URL, values, 400, "payload required")
+
+	slack.Verify = slacker.VerifySignature
+	slack.SigningSecrets = []string{"secret"}
+	values.Add("payload", `{"type":"block_actions"}`)
+	res = postSigned(t, ts.URL, "secret", time.Now(), values)
+	assert.Equal(t, 200, res.StatusCode)
+	res = postSigned(t, ts.URL, "wrong", time.Now(), values)
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestRepliesToInteractionErrors(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	reported := make(chan *slacker.Incident, 1)
+	slack := newInteractiveSlacker()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		reported <- incident
+	})
+	interactions := slack.Interactions()
+	interactions.HandleActionFunc("approve", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		return slacker.Errorf("Already approved.")
+	})
+	interactions.HandleActionFunc("rollback", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		return fmt.Errorf("connecting to db: connection refused")
+	})
+	ts := httptest.NewServer(interactions)
+	defer ts.Close()
+
+	act := func(actionID string) *http.Response {
+		return postInteraction(t, ts.URL, map[string]interface{}{
+			"type":         "block_actions",
+			"token":        "foo",
+			"response_url": hs.URL,
+			"user":         map[string]interface{}{"id": "U123"},
+			"actions":      []interface{}{map[string]interface{}{"type": "button", "action_id": actionID}},
+		})
+	}
+
+	res := act("approve")
+	assert.Equal(t, 200, res.StatusCode)
+	waitMessages(t, hook, 1)
+	res = act("rollback")
+	assert.Equal(t, 200, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "", string(body))
+
+	incident := <-reported
+	assert.Equal(t, "U123", incident.Interaction.User.ID)
+	assert.Equal(t, "connecting to db: connection refused", incident.Err.Error())
+
+	msgs := waitMessages(t, hook, 2)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, &slacker.Message{Text: "Already approved.", ResponseType: slacker.Ephemeral}, msgs[0])
+	assert.Equal(t, slacker.Ephemeral, msgs[1].ResponseType)
+	assert.Equal(t, "Sorry, that didn't work. If it keeps happening, please report incident "+incident.ID+".", msgs[1].Text)
+}
+
+func TestAcknowledgesActionsBeforeHandling(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	release := make(chan struct{})
+	reported := make(chan *slacker.Incident, 1)
+	slack := newInteractiveSlacker()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		reported <- incident
+	})
+	slack.Interactions().HandleActionFunc("rollback", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		<-release
+		panic("boom")
+	})
+	ts := httptest.NewServer(slack.Interactions())
+	defer ts.Close()
+
+	res := postInteraction(t, ts.URL, map[string]interface{}{
+		"type":         "block_actions",
+		"token":        "foo",
+		"response_url": hs.URL,
+		"actions":      []interface{}{map[string]interface{}{"type": "button", "action_id": "rollback"}},
+	})
+	assert.Equal(t, 200, res.StatusCode)
+	close(release)
+
+	incident := <-reported
+	assert.Equal(t, "boom", incident.Panic)
+	assert.Equal(t, "panic handling interaction block_actions: boom", incident.Err.Error())
+
+	msgs := waitMessages(t, hook, 1)
+	assert.Equal(t, "Sorry, something went wrong. If it keeps happening, please report incident "+incident.ID+".", msgs[0].Text)
+	assert.Equal(t, nil, slack.Shutdown(context.Background()))
+}
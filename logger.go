@@ -0,0 +1,247 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the values of secret fields in log records.
+const Redacted = "[redacted]"
+
+// Logger receives structured log records. Fields alternate between keys and
+// values, as with log/slog.
+//
+// The request logger of a command carries the request_id, command, user and
+// team fields, and is available to handlers with LoggerFrom or
+// Command.Logger. Once the command has been handled, a record with its
+// latency and outcome is logged: "ok", "user_error", "error" or "abandoned".
+type Logger interface {
+	Info(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+
+	// With returns a logger which adds `fields` to every record.
+	With(fields ...interface{}) Logger
+}
+
+// NewSlogLogger adapts `l` to a Logger.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+// slogLogger writes records with a slog.Logger.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// Info implements Logger.
+func (l slogLogger) Info(msg string, fields ...interface{}) {
+	l.l.Info(msg, fields...)
+}
+
+// Error implements Logger.
+func (l slogLogger) Error(msg string, fields ...interface{}) {
+	l.l.Error(msg, fields...)
+}
+
+// With implements Logger.
+func (l slogLogger) With(fields ...interface{}) Logger {
+	return slogLogger{l.l.With(fields...)}
+}
+
+// stdLogger writes records with the standard log package, such as
+// `[info] received command request_id=1f2e command=/deploy`.
+type stdLogger struct {
+	fields []interface{}
+}
+
+// Info implements Logger.
+func (l stdLogger) Info(msg string, fields ...interface{}) {
+	l.print("info", msg, fields)
+}
+
+// Error implements Logger.
+func (l stdLogger) Error(msg string, fields ...interface{}) {
+	l.print("error", msg, fields)
+}
+
+// With implements Logger.
+func (l stdLogger) With(fields ...interface{}) Logger {
+	return stdLogger{append(l.fields[:len(l.fields):len(l.fields)], fields...)}
+}
+
+// print writes a record at `level`.
+func (l stdLogger) print(level, msg string, fields []interface{}) {
+	var b strings.Builder
+	b.WriteString("[" + level + "] " + msg)
+	for _, f := range [][]interface{}{l.fields, fields} {
+		for i := 0; i < len(f); {
+			key, value, n := field(f, i)
+			b.WriteString(" " + key + "=" + formatValue(value))
+			i += n
+		}
+	}
+	log.Print(b.String())
+}
+
+// field returns the key and value of the field starting at `i`, and the
+// number of elements it spans. A field may be a slog.Attr, and a value
+// without a key is given the key "!BADKEY", as with log/slog.
+func field(fields []interface{}, i int) (string, interface{}, int) {
+	switch k := fields[i].(type) {
+	case slog.Attr:
+		return k.Key, k.Value, 1
+	case string:
+		if i+1 < len(fields) {
+			return k, fields[i+1], 2
+		}
+	}
+	return "!BADKEY", fields[i], 1
+}
+
+// formatValue formats a field's value, quoting it if needed.
+func formatValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if d, ok := v.(time.Duration); ok {
+		s = d.String()
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// redactor wraps a Logger to replace the values of secret fields.
+type redactor struct {
+	Logger
+}
+
+// Info implements Logger.
+func (r redactor) Info(msg string, fields ...interface{}) {
+	r.Logger.Info(msg, redact(fields)...)
+}
+
+// Error implements Logger.
+func (r redactor) Error(msg string, fields ...interface{}) {
+	r.Logger.Error(msg, redact(fields)...)
+}
+
+// With implements Logger.
+func (r redactor) With(fields ...interface{}) Logger {
+	return redactor{r.Logger.With(redact(fields)...)}
+}
+
+// redact returns `fields` with the values of secret fields replaced.
+func redact(fields []interface{}) []interface{} {
+	var redacted []interface{}
+	for i := 0; i < len(fields); i++ {
+		switch k := fields[i].(type) {
+		case slog.Attr:
+			if secret(k.Key) {
+				redacted = copyFields(redacted, fields)
+				redacted[i] = slog.String(k.Key, Redacted)
+			}
+		case string:
+			if i+1 < len(fields) {
+				if secret(k) {
+					redacted = copyFields(redacted, fields)
+					redacted[i+1] = Redacted
+				}
+				i++
+			}
+		}
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// copyFields returns a copy of `fields`, unless `dst` already is one.
+func copyFields(dst, fields []interface{}) []interface{} {
+	if dst != nil {
+		return dst
+	}
+	return append([]interface{}(nil), fields...)
+}
+
+// secret reports whether the field `key` holds a secret, such as a
+// verification token or a response_url, which lets anyone reply to a command.
+func secret(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range []string{"token", "secret", "password"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return key == "authorization" || key == "response_url"
+}
+
+// defaultLogger is used when no Logger is configured.
+var defaultLogger Logger = redactor{stdLogger{}}
+
+// logger returns the Slacker's logger, which redacts secrets unless
+// LogSecrets is set.
+func (s *Slacker) logger() Logger {
+	if s.Logger == nil {
+		if s.LogSecrets {
+			return stdLogger{}
+		}
+		return defaultLogger
+	}
+	if s.LogSecrets {
+		return s.Logger
+	}
+	return redactor{s.Logger}
+}
+
+// loggerKey is the context key of a request logger.
+type loggerKey struct{}
+
+// WithLogger returns a copy of `ctx` which carries `l`.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFrom returns the logger carried by `ctx`, such as the request logger
+// of a command's context. It defaults to a logger which writes with the
+// standard log package.
+func LoggerFrom(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	return defaultLogger
+}
+
+// Logger returns the command's request logger.
+func (cmd *Command) Logger() Logger {
+	if cmd.logger != nil {
+		return cmd.logger
+	}
+	return LoggerFrom(cmd.Context())
+}
+
+// track starts the request logger of `cmd`, which was received in the
+// request with `id`, and the clock its latency is measured by.
+func (s *Slacker) track(cmd *Command, id string) {
+	if id == "" {
+		id = newID()
+	}
+	cmd.received = time.Now()
+	cmd.logger = s.logger().With(
+		"request_id", id,
+		"command", "/"+cmd.Name,
+		"user", cmd.UserID,
+		"team", cmd.TeamID,
+	)
+}
+
+// done logs that `cmd` was handled with `outcome`.
+func (cmd *Command) done(outcome string, fields ...interface{}) {
+	fields = append([]interface{}{"latency", time.Since(cmd.received), "outcome", outcome}, fields...)
+	cmd.Logger().Info("handled command", fields...)
+}
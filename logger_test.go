@@ -0,0 +1,139 @@
+package slacker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// logBuffer collects the JSON records written by a slog.Logger.
+type logBuffer struct {
+	buf bytes.Buffer
+	sync.Mutex
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+// records returns the records logged so far.
+func (b *logBuffer) records(t *testing.T) []map[string]interface{} {
+	b.Lock()
+	defer b.Unlock()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var r map[string]interface{}
+		err := json.Unmarshal([]byte(line), &r)
+		if err != nil {
+			t.Fatalf("could not decode record %q with error: %s", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func (b *logBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestLogsRequestFields(t *testing.T) {
+	logs := &logBuffer{}
+	slack := slacker.New()
+	slack.Logger = slacker.NewSlogLogger(slog.New(slog.NewJSONHandler(logs, nil)))
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.LoggerFrom(cmd.Context()).Info("deploying", "app", cmd.Text)
+		io.WriteString(w, "Deploying")
+		return nil
+	})
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return errors.New("connection refused")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("text", "api")
+	values.Add("user_id", "U1")
+	values.Add("team_id", "T1")
+	req, _ := http.NewRequest("POST", ts.URL, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Request-Id", "req-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	resp.Body.Close()
+
+	records := logs.records(t)
+	assert.Equal(t, 3, len(records))
+	for _, r := range records {
+		assert.Equal(t, "req-1", r["request_id"])
+		assert.Equal(t, "/deploy", r["command"])
+		assert.Equal(t, "U1", r["user"])
+		assert.Equal(t, "T1", r["team"])
+	}
+	assert.Equal(t, "received command", records[0]["msg"])
+	assert.Equal(t, "deploying", records[1]["msg"])
+	assert.Equal(t, "api", records[1]["app"])
+	assert.Equal(t, "handled command", records[2]["msg"])
+	assert.Equal(t, "ok", records[2]["outcome"])
+	_, ok := records[2]["latency"]
+	assert.Equal(t, true, ok)
+
+	values.Set("command", "/boom")
+	postMessage(t, ts.URL, values)
+	records = logs.records(t)
+	last := records[len(records)-1]
+	assert.Equal(t, "error", last["outcome"])
+	incident := records[len(records)-2]
+	assert.Equal(t, "ERROR", incident["level"])
+	assert.Equal(t, "connection refused", incident["err"])
+	assert.Equal(t, last["incident"], incident["incident"])
+	assert.NotEqual(t, last["request_id"], "req-1")
+}
+
+func TestRedactsSecrets(t *testing.T) {
+	logs := &logBuffer{}
+	slack := slacker.New()
+	slack.Logger = slacker.NewSlogLogger(slog.New(slog.NewJSONHandler(logs, nil)))
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		cmd.Logger().Info("replying", "token", cmd.Token, "response_url", cmd.ResponseURL, slog.String("api_secret", "s3cr3t"))
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "non-foo")
+	testResponse(t, ts.URL, values, 401, "Invalid token")
+	assert.Equal(t, false, strings.Contains(logs.String(), "non-foo"))
+
+	values.Set("token", "foo")
+	values.Add("response_url", "https://hooks.slack.com/commands/1234/5678")
+	http.PostForm(ts.URL, values)
+	records := logs.records(t)
+	replying := records[len(records)-2]
+	assert.Equal(t, "replying", replying["msg"])
+	assert.Equal(t, slacker.Redacted, replying["token"])
+	assert.Equal(t, slacker.Redacted, replying["response_url"])
+	assert.Equal(t, slacker.Redacted, replying["api_secret"])
+}
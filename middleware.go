@@ -0,0 +1,76 @@
+package slacker
+
+import "io"
+
+// Middleware wraps a Handler, to run code before or after it, or instead of
+// it. A middleware may inspect the command or pass a modified copy to the
+// handler, reply itself without invoking the handler, and observe the reply
+// and error of the handler by passing it a Recorder.
+//
+// Middleware runs from the outside in: first the middleware added with
+// Slacker.Use, then the command's middleware, then for routers the middleware
+// added with Router.Use and the subcommand's middleware, each in the order
+// given.
+type Middleware func(Handler) Handler
+
+// Use adds `mw` to the middleware which wraps every command, including
+// commands already registered.
+func (s *Slacker) Use(mw ...Middleware) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.update(func(r *registry) {
+		r.middleware = append(r.middleware[:len(r.middleware):len(r.middleware)], mw...)
+	})
+}
+
+// chain wraps `h` in `mw`, so that the first middleware runs first.
+func chain(h Handler, mw []Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// asHandler adapts `h` to a Handler, which receives the context through
+// Command.Context.
+func asHandler(h ContextHandler) Handler {
+	if a, ok := h.(handlerAdapter); ok {
+		return a.Handler
+	}
+	return ContextHandlerFunc(h.HandleCommandContext)
+}
+
+// Recorder is a ResponseWriter which records a reply, so that middleware can
+// observe or change the reply of a handler before passing it on.
+type Recorder struct {
+	response
+}
+
+// NewRecorder returns an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Result returns the recorded reply, including the text written.
+func (r *Recorder) Result() *Message {
+	return r.message()
+}
+
+// Replay writes the recorded reply to `w`.
+func (r *Recorder) Replay(w io.Writer) error {
+	rw := Response(w)
+	msg := rw.Message()
+	if r.msg.ResponseType != "" {
+		msg.ResponseType = r.msg.ResponseType
+	}
+	msg.Blocks = append(msg.Blocks, r.msg.Blocks...)
+	msg.ReplaceOriginal = msg.ReplaceOriginal || r.msg.ReplaceOriginal
+	msg.DeleteOriginal = msg.DeleteOriginal || r.msg.DeleteOriginal
+
+	if r.text.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(rw, r.text.String())
+	return err
+}
@@ -0,0 +1,195 @@
+package slacker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// trace records the order middleware and handlers run in.
+type trace struct {
+	calls []string
+	sync.Mutex
+}
+
+func (tr *trace) add(call string) {
+	tr.Lock()
+	defer tr.Unlock()
+	tr.calls = append(tr.calls, call)
+}
+
+func (tr *trace) middleware(name string) slacker.Middleware {
+	return func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			tr.add(name + " before")
+			err := next.HandleCommand(w, cmd)
+			tr.add(name + " after")
+			return err
+		})
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	tr := &trace{}
+
+	r := slacker.NewRouter()
+	r.Use(tr.middleware("router 1"), tr.middleware("router 2"))
+	r.HandleFunc("start", func(w io.Writer, cmd *slacker.Command) error {
+		tr.add("handler")
+		io.WriteString(w, "started")
+		return nil
+	}, tr.middleware("subcommand"))
+
+	slack := slacker.New()
+	slack.Use(tr.middleware("global 1"))
+	slack.Handle("deploy", "foo", r, tr.middleware("command 1"), tr.middleware("command 2"))
+
+	// Middleware added after a command is registered still wraps it.
+	slack.Use(tr.middleware("global 2"))
+
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("text", "start")
+	testReply(t, server.URL, values, "started")
+
+	assert.Equal(t, []string{
+		"global 1 before",
+		"global 2 before",
+		"command 1 before",
+		"command 2 before",
+		"router 1 before",
+		"router 2 before",
+		"subcommand before",
+		"handler",
+		"subcommand after",
+		"router 2 after",
+		"router 1 after",
+		"command 2 after",
+		"command 1 after",
+		"global 2 after",
+		"global 1 after",
+	}, tr.calls)
+}
+
+func TestMiddlewareShortCircuits(t *testing.T) {
+	called := false
+	admins := func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			if cmd.UserID != "U1" {
+				slacker.Response(w).SetResponseType(slacker.Ephemeral)
+				fmt.Fprintf(w, "Sorry, only admins may %s.", cmd.Name)
+				return nil
+			}
+			return next.HandleCommand(w, cmd)
+		})
+	}
+
+	slack := slacker.New()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		called = true
+		io.WriteString(w, "deploying")
+		return nil
+	}, admins)
+
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("user_id", "U2")
+	msg := postMessage(t, server.URL, values)
+	assert.Equal(t, "Sorry, only admins may deploy.", msg.Text)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.Equal(t, false, called)
+
+	values.Set("user_id", "U1")
+	testReply(t, server.URL, values, "deploying")
+	assert.Equal(t, true, called)
+}
+
+func TestMiddlewareModifiesCommand(t *testing.T) {
+	lower := func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			c := *cmd
+			c.Text = strings.ToLower(c.Text)
+			return next.HandleCommand(w, &c)
+		})
+	}
+
+	slack := slacker.New()
+	slack.Use(lower)
+	slack.HandleFunc("echo", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		io.WriteString(w, cmd.Text)
+		return nil
+	})
+
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/echo")
+	values.Add("token", "foo")
+	values.Add("text", "HELLO")
+	testReply(t, server.URL, values, "hello")
+}
+
+func TestMiddlewareObservesReply(t *testing.T) {
+	var observed []string
+	observe := func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			rec := slacker.NewRecorder()
+			err := next.HandleCommand(rec, cmd)
+			observed = append(observed, fmt.Sprintf("%q %s %v", rec.Result().Text, rec.Result().ResponseType, err))
+			if err != nil {
+				return errors.New("wrapped: " + err.Error())
+			}
+			return rec.Replay(w)
+		})
+	}
+
+	reported := make(chan error, 1)
+	slack := slacker.New()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		reported <- incident.Err
+	})
+	slack.Use(observe)
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).SetResponseType(slacker.InChannel)
+		io.WriteString(w, "Hello")
+		return nil
+	})
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return errors.New("something exploded")
+	})
+
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	msg := postMessage(t, server.URL, values)
+	assert.Equal(t, "Hello", msg.Text)
+	assert.Equal(t, slacker.InChannel, msg.ResponseType)
+
+	values.Set("command", "/boom")
+	msg = postMessage(t, server.URL, values)
+	assert.T(t, strings.HasPrefix(msg.Text, "Sorry, that didn't work."))
+	assert.Equal(t, "wrapped: something exploded", (<-reported).Error())
+
+	assert.Equal(t, []string{`"Hello" in_channel <nil>`, `""  something exploded`}, observed)
+}
@@ -0,0 +1,391 @@
+package slacker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Patterns is a Handler which matches a command's text against patterns, and
+// invokes the handler of the first pattern which matches with the values it
+// captured in Command.Args. Patterns are tried in the order they were
+// registered, so more specific patterns should be registered first.
+//
+// A pattern is a sequence of:
+//
+//	word              a literal word, matched regardless of case.
+//	<name>            captures a word.
+//	<name:a|b>        captures one of the words a or b.
+//	<name...>         captures the rest of the text as it was typed, and must
+//	                  come last.
+//	[<name>]          optionally captures a word, and must follow the required
+//	                  words.
+//	[--name]          an optional flag, captured as a bool.
+//	[--name <value>]  an optional flag with a value, captured as a string.
+//
+// When no pattern matches, the closest pattern is shown to the user, and
+// "help" shows every pattern.
+type Patterns struct {
+	patterns []*pattern
+	sync.Mutex
+}
+
+// pattern compiled from its text.
+type pattern struct {
+	text    string
+	words   []*patternWord
+	flags   map[string]*patternFlag // maps a flag's name to its spec.
+	handler Handler
+}
+
+// patternWord matches a word of a command's text.
+type patternWord struct {
+	literal  string
+	name     string
+	choices  []string
+	rest     bool
+	optional bool
+}
+
+// patternFlag matches a flag of a command's text.
+type patternFlag struct {
+	name  string
+	value string // name of the value, if the flag takes one.
+}
+
+// NewPatterns returns an empty set of patterns.
+func NewPatterns() *Patterns {
+	return &Patterns{}
+}
+
+// Handle registers `handler` for text matching `pattern`. It panics if the
+// pattern is invalid.
+func (p *Patterns) Handle(pattern string, handler Handler) {
+	pat, err := compilePattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("slacker: invalid pattern %q: %s", pattern, err))
+	}
+	pat.handler = handler
+
+	p.Lock()
+	defer p.Unlock()
+	p.patterns = append(p.patterns, pat)
+}
+
+// HandleFunc registers `handler` function for text matching `pattern`.
+func (p *Patterns) HandleFunc(pattern string, handler func(io.Writer, *Command) error) {
+	p.Handle(pattern, HandlerFunc(handler))
+}
+
+// HandleCommand invokes the handler of the first pattern matching `cmd`, with
+// a copy of `cmd` whose Args hold the captured values.
+func (p *Patterns) HandleCommand(w io.Writer, cmd *Command) error {
+	p.Lock()
+	patterns := p.patterns
+	p.Unlock()
+
+	tokens, err := cmd.Tokens()
+	if err != nil {
+		rw := Response(w)
+		rw.SetResponseType(Ephemeral)
+		fmt.Fprintf(rw, "%s.\n%s", err, p.Usage(cmd))
+		return nil
+	}
+
+	var closest *pattern
+	var closestErr error
+	best := -1
+	for _, pat := range patterns {
+		args, n, err := pat.match(cmd.Text, tokens)
+		if err == nil {
+			c := *cmd
+			c.Args = args
+			return pat.handler.HandleCommand(w, &c)
+		}
+		if n > best {
+			closest, closestErr, best = pat, err, n
+		}
+	}
+
+	rw := Response(w)
+	rw.SetResponseType(Ephemeral)
+	if closest == nil || len(tokens) == 0 || (len(tokens) == 1 && strings.EqualFold(tokens[0].Text, "help")) {
+		io.WriteString(rw, p.Usage(cmd))
+		return nil
+	}
+	fmt.Fprintf(rw, "%s.\nUsage: %s %s\n", closestErr, commandLine(cmd), closest.text)
+	return nil
+}
+
+// Usage returns the usage message listing every pattern, when handling `cmd`.
+func (p *Patterns) Usage(cmd *Command) string {
+	p.Lock()
+	defer p.Unlock()
+
+	var b strings.Builder
+	b.WriteString("Usage:\n")
+	for _, pat := range p.patterns {
+		fmt.Fprintf(&b, "  %s %s\n", commandLine(cmd), pat.text)
+	}
+	return b.String()
+}
+
+// HandlePattern registers `handler` for invocations of a command which match
+// `pattern`, whose first word names the command, such as
+// "deploy <app> to <env:staging|production> [--force]". Patterns registered
+// for the same command are tried in the order they were registered, and must
+// be registered with the same `token`. It panics if the pattern is invalid, if
+// the command is already registered other than with patterns, or if it is
+// registered with another token.
+func (s *Slacker) HandlePattern(pattern, token string, handler Handler) {
+	name, rest := splitWord(pattern)
+	name = strings.TrimPrefix(name, "/")
+
+	s.Lock()
+	defer s.Unlock()
+
+	if p, ok := s.patterns[name]; ok {
+		if s.commands().tokens[name] != token {
+			panic("slacker: patterns for command " + name + " registered with different tokens")
+		}
+		p.Handle(rest, handler)
+		return
+	}
+
+	p := NewPatterns()
+	p.Handle(rest, handler)
+	s.register(name, token, AdaptHandler(p))
+	s.patterns[name] = p
+}
+
+// HandlePatternFunc registers `handler` function for invocations of a command
+// which match `pattern`.
+func (s *Slacker) HandlePatternFunc(pattern, token string, handler func(io.Writer, *Command) error) {
+	s.HandlePattern(pattern, token, HandlerFunc(handler))
+}
+
+// compilePattern parses `text` into a pattern.
+func compilePattern(text string) (*pattern, error) {
+	terms, err := splitPattern(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pattern{
+		text:  strings.Join(terms, " "),
+		flags: make(map[string]*patternFlag),
+	}
+	for _, term := range terms {
+		if n := len(p.words); n > 0 && p.words[n-1].rest {
+			return nil, errors.New("nothing may follow a capture of the rest of the text")
+		}
+
+		if strings.HasPrefix(term, "[") {
+			inner := strings.Fields(term[1 : len(term)-1])
+			switch {
+			case len(inner) > 0 && strings.HasPrefix(inner[0], "--"):
+				f := &patternFlag{name: strings.TrimPrefix(inner[0], "--")}
+				if f.name == "" || len(inner) > 2 {
+					return nil, fmt.Errorf("invalid flag %s", term)
+				}
+				if len(inner) == 2 {
+					w, err := compileCapture(inner[1])
+					if err != nil || w.choices != nil || w.rest {
+						return nil, fmt.Errorf("invalid flag %s", term)
+					}
+					f.value = w.name
+				}
+				p.flags[f.name] = f
+			case len(inner) == 1 && strings.HasPrefix(inner[0], "<"):
+				w, err := compileCapture(inner[0])
+				if err != nil {
+					return nil, err
+				}
+				w.optional = true
+				p.words = append(p.words, w)
+			default:
+				return nil, fmt.Errorf("invalid optional term %s", term)
+			}
+			continue
+		}
+
+		var w *patternWord
+		switch {
+		case strings.HasPrefix(term, "<"):
+			w, err = compileCapture(term)
+			if err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(term, "--"):
+			return nil, fmt.Errorf("flag %s must be optional", term)
+		default:
+			w = &patternWord{literal: term}
+		}
+		if n := len(p.words); n > 0 && p.words[n-1].optional {
+			return nil, fmt.Errorf("%s follows an optional term", term)
+		}
+		p.words = append(p.words, w)
+	}
+	return p, nil
+}
+
+// compileCapture parses a term such as "<env:staging|production>".
+func compileCapture(term string) (*patternWord, error) {
+	if !strings.HasPrefix(term, "<") || !strings.HasSuffix(term, ">") {
+		return nil, fmt.Errorf("invalid capture %s", term)
+	}
+	w := &patternWord{name: term[1 : len(term)-1]}
+	if name, choices, ok := strings.Cut(w.name, ":"); ok {
+		w.name = name
+		w.choices = strings.Split(choices, "|")
+	}
+	if name, ok := strings.CutSuffix(w.name, "..."); ok {
+		w.name = name
+		w.rest = true
+	}
+	if w.name == "" || (w.rest && w.choices != nil) {
+		return nil, fmt.Errorf("invalid capture %s", term)
+	}
+	return w, nil
+}
+
+// splitPattern splits `text` into terms, keeping bracketed terms whole.
+func splitPattern(text string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	depth := 0
+	for _, r := range text {
+		switch {
+		case r == '[':
+			if depth > 0 {
+				return nil, errors.New("nested brackets")
+			}
+			depth++
+		case r == ']':
+			if depth == 0 {
+				return nil, errors.New("unbalanced brackets")
+			}
+			depth--
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+			continue
+		}
+		term.WriteRune(r)
+	}
+	if depth > 0 {
+		return nil, errors.New("unbalanced brackets")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// match matches the `tokens` of `text` against the pattern, and returns the
+// captured values. If they do not match, it returns how many of the pattern's
+// words matched and why the rest did not.
+func (p *pattern) match(text string, tokens []Token) (map[string]interface{}, int, error) {
+	args := make(map[string]interface{})
+	for _, f := range p.flags {
+		if f.value == "" {
+			args[f.name] = false
+		}
+	}
+
+	// Flags may appear anywhere, unless they follow "--" or are part of the
+	// rest of the text.
+	rest := len(p.words)
+	if rest > 0 && p.words[rest-1].rest {
+		rest--
+	} else {
+		rest = -1
+	}
+
+	var positional []Token
+	var flagErr error
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		word := tok.Text
+		if word == "--" && !tok.Quoted {
+			positional = append(positional, tokens[i+1:]...)
+			break
+		}
+		if tok.Quoted || !strings.HasPrefix(word, "--") || (rest >= 0 && len(positional) >= rest) {
+			positional = append(positional, tok)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(word[2:], "=")
+		f, ok := p.flags[name]
+		switch {
+		case !ok:
+			flagErr = fmt.Errorf("unknown flag %s", word)
+		case f.value == "" && hasValue:
+			flagErr = fmt.Errorf("--%s does not take a value", name)
+		case f.value == "":
+			args[name] = true
+		case !hasValue && i+1 == len(tokens):
+			flagErr = fmt.Errorf("--%s requires <%s>", name, f.value)
+		case !hasValue:
+			i++
+			args[name] = tokens[i].Text
+		default:
+			args[name] = value
+		}
+	}
+
+words:
+	for n, w := range p.words {
+		if n == len(positional) {
+			if w.optional {
+				break words
+			}
+			if w.literal != "" {
+				return nil, n, fmt.Errorf("missing %q", w.literal)
+			}
+			return nil, n, fmt.Errorf("missing <%s>", w.name)
+		}
+
+		word := positional[n].Text
+		switch {
+		case w.literal != "":
+			if !strings.EqualFold(word, w.literal) {
+				return nil, n, fmt.Errorf("expected %q, not %q", w.literal, word)
+			}
+		case w.rest:
+			args[w.name] = unescapeEntities(text[positional[n].Start:])
+			break words
+		case w.choices != nil:
+			choice, ok := matchChoice(word, w.choices)
+			if !ok {
+				return nil, n, fmt.Errorf("<%s> must be one of %s, not %q", w.name, strings.Join(w.choices, ", "), word)
+			}
+			args[w.name] = choice
+		default:
+			args[w.name] = word
+		}
+	}
+
+	if len(positional) > len(p.words) && rest < 0 {
+		return nil, len(p.words), fmt.Errorf("unexpected %q", positional[len(p.words)].Text)
+	}
+	if flagErr != nil {
+		return nil, len(p.words), flagErr
+	}
+	return args, len(p.words), nil
+}
+
+// matchChoice returns the choice which `word` names, regardless of case.
+func matchChoice(word string, choices []string) (string, bool) {
+	for _, c := range choices {
+		if strings.EqualFold(word, c) {
+			return c, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,134 @@
+package slacker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func newPatternSlacker() *slacker.Slacker {
+	slack := slacker.New()
+	slack.HandlePatternFunc("deploy <app> to <env:staging|production> [--force] [--tag <tag>]", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "deploy %s to %s force=%v tag=%q", cmd.Arg("app"), cmd.Arg("env"), cmd.Args["force"], cmd.Arg("tag"))
+		return nil
+	})
+	slack.HandlePatternFunc("/deploy status [<app>]", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "status %q", cmd.Arg("app"))
+		return nil
+	})
+	slack.HandlePatternFunc("deploy note <app> <message...>", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "note %s: %s", cmd.Arg("app"), cmd.Arg("message"))
+		return nil
+	})
+	return slack
+}
+
+func TestPatternsCapture(t *testing.T) {
+	server := httptest.NewServer(newPatternSlacker())
+	defer server.Close()
+
+	assert.Equal(t, `deploy api to production force=false tag=""`, postCommand(t, server.URL, "deploy", "api to PRODUCTION").Text)
+	assert.Equal(t, `deploy api to staging force=true tag="v2"`, postCommand(t, server.URL, "deploy", "--force api To staging --tag v2").Text)
+	assert.Equal(t, `deploy api to staging force=false tag="v2"`, postCommand(t, server.URL, "deploy", "api to staging --tag=v2").Text)
+	assert.Equal(t, `status ""`, postCommand(t, server.URL, "deploy", "status").Text)
+	assert.Equal(t, `status "api"`, postCommand(t, server.URL, "deploy", "status api").Text)
+	assert.Equal(t, "note api: rolled back --force  by &hand", postCommand(t, server.URL, "deploy", "note api rolled back --force  by &amp;hand").Text)
+}
+
+func TestPatternsShowClosestPattern(t *testing.T) {
+	server := httptest.NewServer(newPatternSlacker())
+	defer server.Close()
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"api to prod", "<env> must be one of staging, production, not \"prod\".\nUsage: /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n"},
+		{"api", "missing \"to\".\nUsage: /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n"},
+		{"api to staging --quick", "unknown flag --quick.\nUsage: /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n"},
+		{"api to staging --tag", "--tag requires <tag>.\nUsage: /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n"},
+		{"status api now", "unexpected \"now\".\nUsage: /deploy status [<app>]\n"},
+		{"note api", "missing <message>.\nUsage: /deploy note <app> <message...>\n"},
+	}
+	for _, test := range tests {
+		msg := postCommand(t, server.URL, "deploy", test.text)
+		assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+		assert.Equal(t, test.expected, msg.Text)
+	}
+}
+
+func TestPatternsHelp(t *testing.T) {
+	server := httptest.NewServer(newPatternSlacker())
+	defer server.Close()
+
+	usage := "Usage:\n" +
+		"  /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n" +
+		"  /deploy status [<app>]\n" +
+		"  /deploy note <app> <message...>\n"
+	assert.Equal(t, usage, postCommand(t, server.URL, "deploy", "help").Text)
+	assert.Equal(t, usage, postCommand(t, server.URL, "deploy", "").Text)
+}
+
+func TestPatternsInRouter(t *testing.T) {
+	p := slacker.NewPatterns()
+	p.HandleFunc("<table> [--dry-run]", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "migrating %s dry-run=%v", cmd.Arg("table"), cmd.Args["dry-run"])
+		return nil
+	})
+	r := slacker.NewRouter()
+	r.Handle("migrate", p)
+
+	var b strings.Builder
+	r.HandleCommand(&b, &slacker.Command{Name: "db", Text: "migrate users --dry-run"})
+	assert.Equal(t, "migrating users dry-run=true", b.String())
+
+	b.Reset()
+	r.HandleCommand(&b, &slacker.Command{Name: "db", Text: "migrate --dry-run"})
+	assert.Equal(t, "missing <table>.\nUsage: /db migrate <table> [--dry-run]\n", b.String())
+}
+
+func TestInvalidPatternPanics(t *testing.T) {
+	for _, pattern := range []string{
+		"deploy <app",
+		"deploy [<app>] <env>",
+		"deploy <rest...> more",
+		"deploy --force",
+		"deploy [--force",
+		"deploy [--tag a b]",
+	} {
+		func() {
+			defer func() {
+				assert.NotEqual(t, nil, recover())
+			}()
+			slacker.New().HandlePatternFunc(pattern, "foo", nil)
+		}()
+	}
+}
+
+func TestPatternTokenMismatchPanics(t *testing.T) {
+	slack := slacker.New()
+	slack.HandlePatternFunc("deploy <app>", "foo", nil)
+	slack.HandlePatternFunc("deploy status", "foo", nil)
+
+	defer func() {
+		assert.NotEqual(t, nil, recover())
+	}()
+	slack.HandlePatternFunc("deploy note <app>", "bar", nil)
+}
+
+func TestPatternsTokenizeText(t *testing.T) {
+	server := httptest.NewServer(newPatternSlacker())
+	defer server.Close()
+
+	assert.Equal(t, `deploy web app to staging force=false tag="--force"`, postCommand(t, server.URL, "deploy", "“web app” to staging --tag '--force'").Text)
+	assert.Equal(t, `status "--force"`, postCommand(t, server.URL, "deploy", `status "--force"`).Text)
+	assert.Equal(t, "unterminated “ quote.\nUsage:\n"+
+		"  /deploy <app> to <env:staging|production> [--force] [--tag <tag>]\n"+
+		"  /deploy status [<app>]\n"+
+		"  /deploy note <app> <message...>\n", postCommand(t, server.URL, "deploy", "“web app to staging").Text)
+}
@@ -0,0 +1,122 @@
+package slacker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+)
+
+// DefaultPanicMessage apologizes to users whose command panicked. It is
+// formatted with the ID of the incident.
+const DefaultPanicMessage = "Sorry, something went wrong. If it keeps happening, please report incident %s."
+
+// Incident describes a command which failed in a way users are not shown the
+// details of.
+type Incident struct {
+	// ID identifies the incident to users and in logs.
+	ID string
+
+	// Command which failed, which identifies the user who sent it.
+	Command *Command
+
+	// Interaction whose handler failed, for incidents which are not caused by
+	// a command.
+	Interaction *Interaction
+
+	// Event whose handler failed, for incidents which are not caused by a
+	// command.
+	Event *Event
+
+	// Err describes the failure.
+	Err error
+
+	// Panic is the value the handler panicked with, and Stack the stack of its
+	// goroutine when it did.
+	Panic interface{}
+	Stack []byte
+}
+
+// Error implements error.
+func (i *Incident) Error() string {
+	return fmt.Sprintf("incident %s: %s", i.ID, i.Err)
+}
+
+// Unwrap returns the error which caused the incident.
+func (i *Incident) Unwrap() error {
+	return i.Err
+}
+
+// Reporter is notified of incidents, such as to forward them to an error
+// tracker.
+type Reporter interface {
+	Report(ctx context.Context, incident *Incident)
+}
+
+// ReporterFunc convenience type.
+type ReporterFunc func(ctx context.Context, incident *Incident)
+
+// Report invokes itself.
+func (f ReporterFunc) Report(ctx context.Context, incident *Incident) {
+	f(ctx, incident)
+}
+
+// newID returns a random ID for an incident or request.
+func newID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// report logs `incident` and passes it to the Reporter.
+func (s *Slacker) report(ctx context.Context, incident *Incident) {
+	log := s.logger()
+	if incident.Command != nil {
+		log = incident.Command.Logger()
+	}
+	if in := incident.Interaction; in != nil {
+		log = log.With("interaction", in.Type, "user", in.User.ID, "team", in.Team.ID)
+	}
+	if ev := incident.Event; ev != nil {
+		log = log.With("event", ev.Type, "event_id", ev.Envelope.EventID)
+	}
+	if incident.Stack != nil {
+		log.Error("incident", "incident", incident.ID, "err", incident.Err, "stack", string(incident.Stack))
+	} else {
+		log.Error("incident", "incident", incident.ID, "err", incident.Err)
+	}
+
+	if s.Reporter != nil {
+		s.Reporter.Report(ctx, incident)
+	}
+}
+
+// panicked reports that the handler of `what` panicked with `v` as
+// `incident`, which it returns. It must be called by the function which
+// recovered the panic, so that the stack is the handler's.
+func (s *Slacker) panicked(ctx context.Context, incident *Incident, what string, v interface{}) *Incident {
+	incident.ID = newID()
+	incident.Err = fmt.Errorf("panic handling %s: %v", what, v)
+	incident.Panic = v
+	incident.Stack = debug.Stack()
+	s.report(ctx, incident)
+	return incident
+}
+
+// invoke calls the handler. A panic is recovered, since the handler does not
+// run on the request's goroutine, and reported as an incident, which replaces
+// the handler's reply with an apology.
+func (s *Slacker) invoke(ctx context.Context, h ContextHandler, cmd *Command) (res *result) {
+	res = &result{}
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		res.res = response{}
+		res.err = s.panicked(ctx, &Incident{Command: cmd}, "/"+cmd.Name, v)
+	}()
+	res.err = h.HandleCommandContext(ctx, &res.res, cmd)
+	return res
+}
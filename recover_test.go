@@ -0,0 +1,110 @@
+package slacker_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func TestRecoversPanics(t *testing.T) {
+	incidents := make(chan *slacker.Incident, 1)
+	slack := slacker.New()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		incidents <- incident
+	})
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprint(w, "partial reply")
+		panic("something exploded")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/boom")
+	values.Add("token", "foo")
+	values.Add("user_id", "U1")
+	msg := postMessage(t, ts.URL, values)
+
+	incident := <-incidents
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.Equal(t, fmt.Sprintf("Sorry, something went wrong. If it keeps happening, please report incident %s.", incident.ID), msg.Text)
+	assert.T(t, regexp.MustCompile(`^[0-9a-f]{12}$`).MatchString(incident.ID))
+
+	assert.Equal(t, "boom", incident.Command.Name)
+	assert.Equal(t, "U1", incident.Command.UserID)
+	assert.Equal(t, "something exploded", incident.Panic)
+	assert.Equal(t, "panic handling /boom: something exploded", incident.Err.Error())
+	assert.T(t, strings.Contains(string(incident.Stack), "recover_test.go"))
+}
+
+func TestPanicMessage(t *testing.T) {
+	slack := slacker.New()
+	slack.PanicMessage = "Oops (%s)"
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		var m map[string]int
+		m["x"]++
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/boom")
+	values.Add("token", "foo")
+	msg := postMessage(t, ts.URL, values)
+	assert.T(t, regexp.MustCompile(`^Oops \([0-9a-f]{12}\)$`).MatchString(msg.Text))
+}
+
+func TestRecoversPanicsInSlowCommands(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	slack := slacker.New()
+	slack.AckTimeout = 10 * time.Millisecond
+	slack.HandleFunc("boom", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		time.Sleep(50 * time.Millisecond)
+		panic("something exploded")
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/boom")
+	values.Add("token", "foo")
+	values.Add("response_url", hs.URL)
+	testReply(t, ts.URL, values, slacker.DefaultAckMessage)
+
+	msgs := waitMessages(t, hook, 1)
+	assert.Equal(t, slacker.Ephemeral, msgs[0].ResponseType)
+	assert.T(t, strings.HasPrefix(msgs[0].Text, "Sorry, something went wrong."))
+}
+
+func TestRecoversPanicsInMiddleware(t *testing.T) {
+	slack := slacker.New()
+	slack.Use(func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			panic("middleware exploded")
+		})
+	})
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	msg := postMessage(t, ts.URL, values)
+	assert.T(t, strings.HasPrefix(msg.Text, "Sorry, something went wrong."))
+}
@@ -0,0 +1,131 @@
+package slacker
+
+import (
+	"sort"
+	"time"
+)
+
+// registry of commands. A registry is never modified once it is published, so
+// it can be read without locking; changes are made to a copy which replaces
+// it.
+type registry struct {
+	handlers map[string]ContextHandler // maps a command to its handler.
+	tokens   map[string]string         // maps a command to its token.
+	timeouts map[string]time.Duration  // maps a command to its time budget.
+
+	middleware []Middleware // wraps every command.
+}
+
+// newRegistry returns an empty registry.
+func newRegistry() *registry {
+	return &registry{
+		handlers: make(map[string]ContextHandler),
+		tokens:   make(map[string]string),
+		timeouts: make(map[string]time.Duration),
+	}
+}
+
+// clone returns a copy of the registry.
+func (r *registry) clone() *registry {
+	c := &registry{
+		handlers: make(map[string]ContextHandler, len(r.handlers)),
+		tokens:   make(map[string]string, len(r.tokens)),
+		timeouts: make(map[string]time.Duration, len(r.timeouts)),
+
+		middleware: r.middleware,
+	}
+	for k, v := range r.handlers {
+		c.handlers[k] = v
+	}
+	for k, v := range r.tokens {
+		c.tokens[k] = v
+	}
+	for k, v := range r.timeouts {
+		c.timeouts[k] = v
+	}
+	return c
+}
+
+// commands returns the current registry.
+func (s *Slacker) commands() *registry {
+	return s.registry.Load()
+}
+
+// update publishes a copy of the registry changed by `fn`. The caller must
+// hold the lock, so that concurrent changes are not lost.
+func (s *Slacker) update(fn func(r *registry)) {
+	r := s.commands().clone()
+	fn(r)
+	s.registry.Store(r)
+}
+
+// handler returns the handler of command `name`, wrapped in the middleware
+// added with Use.
+func (s *Slacker) handler(name string) (ContextHandler, bool) {
+	r := s.commands()
+	h, ok := r.handlers[name]
+	if !ok || len(r.middleware) == 0 {
+		return h, ok
+	}
+	return AdaptHandler(chain(asHandler(h), r.middleware)), true
+}
+
+// register registers `handler` for command `name` with `token`, or panics if
+// the command is already registered. The caller must hold the lock.
+func (s *Slacker) register(name, token string, handler ContextHandler) {
+	if _, ok := s.commands().handlers[name]; ok {
+		panic("slacker: multiple registrations for command " + name)
+	}
+	s.update(func(r *registry) {
+		r.handlers[name] = handler
+		r.tokens[name] = token
+	})
+}
+
+// Unhandle removes command `name`, and reports whether it was registered.
+// Invocations already running are unaffected, and its time budget is kept in
+// case it is registered again.
+func (s *Slacker) Unhandle(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.commands().handlers[name]; !ok {
+		return false
+	}
+	s.update(func(r *registry) {
+		delete(r.handlers, name)
+		delete(r.tokens, name)
+	})
+	delete(s.patterns, name)
+	return true
+}
+
+// Replace registers `handler` for command `name` with `token`, wrapped in
+// `mw`, replacing the command's handler if it is already registered.
+func (s *Slacker) Replace(name, token string, handler Handler, mw ...Middleware) {
+	s.ReplaceContext(name, token, AdaptHandler(chain(handler, mw)))
+}
+
+// ReplaceContext registers context aware `handler` for command `name` with
+// `token`, replacing the command's handler if it is already registered.
+func (s *Slacker) ReplaceContext(name, token string, handler ContextHandler) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.update(func(r *registry) {
+		r.handlers[name] = handler
+		r.tokens[name] = token
+	})
+	delete(s.patterns, name)
+}
+
+// Commands returns the names of the registered commands, in order.
+func (s *Slacker) Commands() []string {
+	handlers := s.commands().handlers
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,143 @@
+package slacker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func hello(name string) func(io.Writer, *slacker.Command) error {
+	return func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "Hello from %s", name)
+		return nil
+	}
+}
+
+func TestUnhandle(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("hello", "foo", hello("v1"))
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	testReply(t, server.URL, values, "Hello from v1")
+
+	assert.Equal(t, true, slack.Unhandle("hello"))
+	assert.Equal(t, false, slack.Unhandle("hello"))
+	assert.Equal(t, false, slack.ValidToken("hello", "foo"))
+	testResponse(t, server.URL, values, 400, "Invalid command")
+
+	slack.HandleFunc("hello", "foo", hello("v2"))
+	testReply(t, server.URL, values, "Hello from v2")
+}
+
+func TestReplace(t *testing.T) {
+	slack := slacker.New()
+	slack.Replace("hello", "foo", slacker.HandlerFunc(hello("v1")))
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	testReply(t, server.URL, values, "Hello from v1")
+
+	slack.Replace("hello", "bar", slacker.HandlerFunc(hello("v2")))
+	testResponse(t, server.URL, values, 401, "Invalid token")
+	values.Set("token", "bar")
+	testReply(t, server.URL, values, "Hello from v2")
+}
+
+func TestReplacePatterns(t *testing.T) {
+	slack := slacker.New()
+	slack.HandlePatternFunc("hello <name>", "foo", hello("patterns"))
+	slack.Replace("hello", "foo", slacker.HandlerFunc(hello("v2")))
+	slack.Unhandle("hello")
+
+	// The command's patterns were removed along with it.
+	slack.HandlePatternFunc("hello <name>", "foo", hello("patterns"))
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	values.Add("text", "bob")
+	testReply(t, server.URL, values, "Hello from patterns")
+}
+
+func TestCommands(t *testing.T) {
+	slack := slacker.New()
+	assert.Equal(t, []string{}, slack.Commands())
+
+	slack.HandleFunc("hello", "foo", hello("v1"))
+	slack.HandleFunc("deploy", "foo", hello("v1"))
+	slack.HandlePatternFunc("boom <what>", "foo", hello("v1"))
+	assert.Equal(t, []string{"boom", "deploy", "hello"}, slack.Commands())
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("hello", "foo", hello("v1"))
+
+	for _, register := range []func(){
+		func() { slack.HandleFunc("hello", "foo", hello("v2")) },
+		func() { slack.HandlePatternFunc("hello <name>", "foo", hello("v2")) },
+	} {
+		func() {
+			defer func() {
+				assert.Equal(t, "slacker: multiple registrations for command hello", recover())
+			}()
+			register()
+		}()
+	}
+}
+
+// Registering commands while requests are served is safe, which
+// `go test -race` checks.
+func TestRegistersWhileServing(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("stable", "foo", hello("stable"))
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("flaky%d", i)
+			for j := 0; j < 100; j++ {
+				slack.HandleFunc(name, "foo", hello(name))
+				slack.SetTimeout(name, 0)
+				slack.Replace(name, "foo", slacker.HandlerFunc(hello(name)))
+				slack.Commands()
+				slack.Unhandle(name)
+			}
+		}(i)
+	}
+
+	for i := 0; i < 20; i++ {
+		values := url.Values{}
+		values.Add("command", "/stable")
+		values.Add("token", "foo")
+		testReply(t, server.URL, values, "Hello from stable")
+
+		values.Set("command", fmt.Sprintf("/flaky%d", i%4))
+		res, err := http.PostForm(server.URL, values)
+		assert.Equal(t, nil, err)
+		res.Body.Close()
+		assert.T(t, res.StatusCode == 200 || res.StatusCode == 400)
+	}
+
+	wg.Wait()
+}
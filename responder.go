@@ -0,0 +1,182 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits Slack places on delayed responses to a single response_url.
+const (
+	MaxFollowups   = 5
+	FollowupWindow = 30 * time.Minute
+)
+
+// Defaults for retrying delayed responses.
+const (
+	DefaultRetries = 3
+	DefaultBackoff = 500 * time.Millisecond
+)
+
+// Delayed response errors.
+var (
+	ErrNoResponseURL    = errors.New("slacker: command has no response_url")
+	ErrTooManyFollowups = errors.New("slacker: too many delayed responses")
+)
+
+// ResponseError is returned when Slack rejects a delayed response.
+type ResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements error.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("slacker: response_url returned %d: %s", e.StatusCode, e.Body)
+}
+
+// temporary reports whether the request may succeed if retried.
+func (e *ResponseError) temporary() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// Responder posts delayed responses to a command's response_url. It is safe
+// for concurrent use, and enforces Slack's limit of MaxFollowups posts within
+// FollowupWindow.
+type Responder struct {
+	URL     string
+	Client  *http.Client  // defaults to http.DefaultClient.
+	Retries int           // defaults to DefaultRetries.
+	Backoff time.Duration // defaults to DefaultBackoff, doubled after each attempt.
+
+	mu   sync.Mutex
+	sent []time.Time // times of posts within the window.
+}
+
+// NewResponder returns a responder for `url` using `client`.
+func NewResponder(url string, client *http.Client) *Responder {
+	return &Responder{URL: url, Client: client}
+}
+
+// Post sends `msg` to the response_url, retrying temporary failures. It
+// returns ValidationErrors if the message is invalid, ErrTooManyFollowups once
+// the limit is reached, and a *ResponseError if Slack rejects the message.
+func (r *Responder) Post(ctx context.Context, msg *Message) error {
+	if r.URL == "" {
+		return ErrNoResponseURL
+	}
+
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	at, err := r.reserve()
+	if err != nil {
+		return err
+	}
+
+	retries := r.Retries
+	if retries <= 0 {
+		retries = DefaultRetries
+	}
+	backoff := r.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = r.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if e, ok := err.(*ResponseError); ok && !e.temporary() {
+			break
+		}
+		if attempt >= retries {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			r.release(at)
+			return ctx.Err()
+		}
+	}
+
+	r.release(at)
+	return err
+}
+
+// post makes a single attempt at sending `body`.
+func (r *Responder) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequest("POST", r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return &ResponseError{StatusCode: res.StatusCode, Body: string(b)}
+	}
+	return nil
+}
+
+// reserve records a post, failing if the limit has been reached.
+func (r *Responder) reserve() (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	sent := r.sent[:0]
+	for _, t := range r.sent {
+		if now.Sub(t) < FollowupWindow {
+			sent = append(sent, t)
+		}
+	}
+	r.sent = sent
+
+	if len(r.sent) >= MaxFollowups {
+		return now, ErrTooManyFollowups
+	}
+	r.sent = append(r.sent, now)
+	return now, nil
+}
+
+// release forgets a post reserved at `at` which was never delivered.
+func (r *Responder) release(at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range r.sent {
+		if t.Equal(at) {
+			r.sent = append(r.sent[:i], r.sent[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package slacker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// responseURL records messages posted to it, failing the first `failures`
+// requests with `status`.
+type responseURL struct {
+	sync.Mutex
+	failures int
+	status   int
+	messages []*slacker.Message
+}
+
+func (u *responseURL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.Lock()
+	defer u.Unlock()
+
+	if u.failures > 0 {
+		u.failures--
+		http.Error(w, "failed", u.status)
+		return
+	}
+
+	var msg slacker.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	u.messages = append(u.messages, &msg)
+}
+
+func (u *responseURL) Messages() []*slacker.Message {
+	u.Lock()
+	defer u.Unlock()
+	return u.messages
+}
+
+func TestFollowupFromHandler(t *testing.T) {
+	hook := &responseURL{}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	done := make(chan error)
+	slack := slacker.New()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprint(w, "Deploying")
+		go func() {
+			done <- cmd.Followup(context.Background(), &slacker.Message{Text: "Deployed"})
+		}()
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("response_url", hs.URL)
+
+	res, err := http.PostForm(ts.URL, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	assert.Equal(t, 200, res.StatusCode)
+
+	assert.Equal(t, nil, <-done)
+	assert.Equal(t, []*slacker.Message{{Text: "Deployed"}}, hook.Messages())
+}
+
+func TestResponderRetries(t *testing.T) {
+	hook := &responseURL{failures: 2, status: 503}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	r := slacker.NewResponder(hs.URL, nil)
+	r.Backoff = time.Millisecond
+
+	err := r.Post(context.Background(), &slacker.Message{Text: "hello"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(hook.Messages()))
+}
+
+func TestResponderReportsErrors(t *testing.T) {
+	hook := &responseURL{failures: 10, status: 404}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	r := slacker.NewResponder(hs.URL, nil)
+	r.Backoff = time.Millisecond
+
+	err := r.Post(context.Background(), &slacker.Message{Text: "hello"})
+	rerr, ok := err.(*slacker.ResponseError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 404, rerr.StatusCode)
+
+	// Client errors are not retried.
+	hook.Lock()
+	assert.Equal(t, 9, hook.failures)
+	hook.Unlock()
+
+	err = slacker.NewResponder("", nil).Post(context.Background(), &slacker.Message{})
+	assert.Equal(t, slacker.ErrNoResponseURL, err)
+}
+
+func TestResponderLimitsFollowups(t *testing.T) {
+	hook := &responseURL{failures: 1, status: 400}
+	hs := httptest.NewServer(hook)
+	defer hs.Close()
+
+	r := slacker.NewResponder(hs.URL, nil)
+
+	// Failed posts do not count towards the limit.
+	assert.NotEqual(t, nil, r.Post(context.Background(), &slacker.Message{Text: "failed"}))
+
+	for i := 0; i < slacker.MaxFollowups; i++ {
+		assert.Equal(t, nil, r.Post(context.Background(), &slacker.Message{Text: "hello"}))
+	}
+	assert.Equal(t, slacker.ErrTooManyFollowups, r.Post(context.Background(), &slacker.Message{Text: "hello"}))
+	assert.Equal(t, slacker.MaxFollowups, len(hook.Messages()))
+}
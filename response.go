@@ -0,0 +1,117 @@
+package slacker
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ResponseType selects who sees a reply.
+type ResponseType string
+
+// Response types.
+const (
+	// Ephemeral replies are only shown to the user who sent the command. This
+	// is Slack's default.
+	Ephemeral ResponseType = "ephemeral"
+
+	// InChannel replies are shown to everyone in the channel.
+	InChannel ResponseType = "in_channel"
+)
+
+// Message is a reply to a command.
+type Message struct {
+	Text         string       `json:"text,omitempty"`
+	ResponseType ResponseType `json:"response_type,omitempty"`
+
+	// Blocks are Block Kit layout blocks, such as those built by package
+	// blocks. Any value which marshals to a block may be used.
+	Blocks []interface{} `json:"blocks,omitempty"`
+
+	// ReplaceOriginal and DeleteOriginal apply to delayed responses, and act on
+	// the message which triggered the response.
+	ReplaceOriginal bool `json:"replace_original,omitempty"`
+	DeleteOriginal  bool `json:"delete_original,omitempty"`
+}
+
+// empty reports whether there is nothing to send.
+func (m *Message) empty() bool {
+	return m.Text == "" && len(m.Blocks) == 0 && !m.ReplaceOriginal && !m.DeleteOriginal
+}
+
+// ResponseWriter is the writer passed to handlers. Plain text may be written
+// to it as to any io.Writer, and handlers which need more control over their
+// reply can reach it with a type assertion or Response.
+type ResponseWriter interface {
+	io.Writer
+
+	// Message returns the reply being written. Text written to the
+	// ResponseWriter is appended to the message's Text when it is sent.
+	Message() *Message
+
+	// SetResponseType selects who sees the reply.
+	SetResponseType(t ResponseType)
+
+	// AddBlocks appends Block Kit blocks to the reply.
+	AddBlocks(blocks ...interface{})
+}
+
+// Response returns `w` as a ResponseWriter. Writers which are not already a
+// ResponseWriter, such as a buffer in a test, are wrapped so that text is
+// still written to `w`.
+func Response(w io.Writer) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+	return &response{w: w}
+}
+
+// response implements ResponseWriter.
+type response struct {
+	w    io.Writer // optional writer text is passed through to.
+	msg  Message
+	text strings.Builder
+}
+
+// Write implements io.Writer.
+func (r *response) Write(p []byte) (int, error) {
+	if r.w != nil {
+		return r.w.Write(p)
+	}
+	return r.text.Write(p)
+}
+
+// Message implements ResponseWriter.
+func (r *response) Message() *Message {
+	return &r.msg
+}
+
+// SetResponseType implements ResponseWriter.
+func (r *response) SetResponseType(t ResponseType) {
+	r.msg.ResponseType = t
+}
+
+// AddBlocks implements ResponseWriter.
+func (r *response) AddBlocks(blocks ...interface{}) {
+	r.msg.Blocks = append(r.msg.Blocks, blocks...)
+}
+
+// message returns the reply with the text written so far.
+func (r *response) message() *Message {
+	msg := r.msg
+	msg.Text += r.text.String()
+	return &msg
+}
+
+// writeMessage writes `msg` as the reply to a request. Nothing is written for
+// an empty message, which Slack accepts as an acknowledgement.
+func writeMessage(w http.ResponseWriter, msg *Message) error {
+	if msg.empty() {
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(msg)
+}
@@ -0,0 +1,125 @@
+package slacker_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+	"github.com/segmentio/go-slacker/blocks"
+)
+
+func TestResponseWriter(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		rw := w.(slacker.ResponseWriter)
+		rw.SetResponseType(slacker.InChannel)
+		rw.AddBlocks(map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": "*Hello*"},
+		})
+		rw.Message().ReplaceOriginal = true
+		fmt.Fprint(w, "Hello")
+		fmt.Fprint(w, " World")
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, "Hello World", msg.Text)
+	assert.Equal(t, slacker.InChannel, msg.ResponseType)
+	assert.Equal(t, true, msg.ReplaceOriginal)
+	assert.Equal(t, false, msg.DeleteOriginal)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"type": "section",
+		"text": map[string]interface{}{"type": "mrkdwn", "text": "*Hello*"},
+	}}, msg.Blocks)
+}
+
+func TestRepliesWithBlocks(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).AddBlocks(
+			blocks.Section(blocks.Markdown("Deploy *api*?")),
+			blocks.Actions(blocks.Button("deploy", "Deploy")),
+		)
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, 2, len(msg.Blocks))
+	assert.Equal(t, "section", msg.Blocks[0].(map[string]interface{})["type"])
+	assert.Equal(t, "actions", msg.Blocks[1].(map[string]interface{})["type"])
+}
+
+func TestEmptyReply(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+
+	res, err := http.PostForm(ts.URL, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	var buf bytes.Buffer
+	buf.ReadFrom(res.Body)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestResponse(t *testing.T) {
+	var buf bytes.Buffer
+	rw := slacker.Response(&buf)
+	rw.SetResponseType(slacker.Ephemeral)
+	fmt.Fprint(rw, "Hello")
+
+	assert.Equal(t, "Hello", buf.String())
+	assert.Equal(t, slacker.Ephemeral, rw.Message().ResponseType)
+	assert.Equal(t, rw, slacker.Response(rw))
+}
+
+func TestWritesJSON(t *testing.T) {
+	slack := slacker.New()
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).SetResponseType(slacker.InChannel)
+		fmt.Fprint(w, "Hello <World>")
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+
+	res, err := http.PostForm(ts.URL, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	var buf bytes.Buffer
+	buf.ReadFrom(res.Body)
+	assert.Equal(t, `{"text":"Hello <World>","response_type":"in_channel"}`+"\n", buf.String())
+}
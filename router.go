@@ -0,0 +1,152 @@
+package slacker
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Router is a Handler which dispatches on the first word of a command's text,
+// as split by Tokenize, to nested handlers, so that "/deploy start api" runs
+// the handler registered for "start" with the text "api". Routers may be
+// nested to any depth.
+//
+// A usage message listing the subcommands is written when the text is empty,
+// is "help", or names an unknown subcommand.
+type Router struct {
+	routes     map[string]*route // maps a subcommand to its route.
+	middleware []Middleware      // wraps every subcommand.
+	sync.Mutex
+}
+
+// route to a subcommand.
+type route struct {
+	handler     Handler
+	description string
+}
+
+// NewRouter returns an empty router.
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]*route)}
+}
+
+// Handle registers `handler` for subcommand `name`, wrapped in `mw`.
+func (r *Router) Handle(name string, handler Handler, mw ...Middleware) {
+	r.Lock()
+	defer r.Unlock()
+
+	name = strings.ToLower(name)
+	rt, ok := r.routes[name]
+	if !ok {
+		rt = &route{}
+		r.routes[name] = rt
+	}
+	rt.handler = chain(handler, mw)
+}
+
+// Use adds `mw` to the middleware which wraps every subcommand, inside the
+// middleware of the command the router handles and outside the subcommand's
+// own middleware.
+func (r *Router) Use(mw ...Middleware) {
+	r.Lock()
+	defer r.Unlock()
+	r.middleware = append(r.middleware[:len(r.middleware):len(r.middleware)], mw...)
+}
+
+// HandleFunc registers `handler` function for subcommand `name`, wrapped in
+// `mw`.
+func (r *Router) HandleFunc(name string, handler func(io.Writer, *Command) error, mw ...Middleware) {
+	r.Handle(name, HandlerFunc(handler), mw...)
+}
+
+// Describe sets the description of subcommand `name` shown in the usage
+// message.
+func (r *Router) Describe(name, description string) {
+	r.Lock()
+	defer r.Unlock()
+
+	name = strings.ToLower(name)
+	rt, ok := r.routes[name]
+	if !ok {
+		rt = &route{}
+		r.routes[name] = rt
+	}
+	rt.description = description
+}
+
+// HandleCommand dispatches `cmd` to the handler of the subcommand named by
+// the first word of its text. The handler receives a copy of `cmd` with that
+// word removed from its Text and appended to its Path.
+func (r *Router) HandleCommand(w io.Writer, cmd *Command) error {
+	tokens, err := cmd.Tokens()
+	if err != nil {
+		rw := Response(w)
+		rw.SetResponseType(Ephemeral)
+		fmt.Fprintf(rw, "%s.\n\n%s", err, r.Usage(cmd))
+		return nil
+	}
+
+	var name, text string
+	if len(tokens) > 0 {
+		name = strings.ToLower(tokens[0].Text)
+		text = strings.TrimSpace(cmd.Text[tokens[0].End:])
+	}
+
+	r.Lock()
+	rt, ok := r.routes[name]
+	mw := r.middleware
+	r.Unlock()
+
+	if !ok || rt.handler == nil {
+		rw := Response(w)
+		rw.SetResponseType(Ephemeral)
+		if name != "" && name != "help" {
+			fmt.Fprintf(rw, "Unknown subcommand %q.\n\n", name)
+		}
+		io.WriteString(rw, r.Usage(cmd))
+		return nil
+	}
+
+	sub := *cmd
+	sub.Text = text
+	sub.Path = append(append([]string(nil), cmd.Path...), name)
+	return chain(rt.handler, mw).HandleCommand(w, &sub)
+}
+
+// Usage returns the usage message of the router when it handles `cmd`.
+func (r *Router) Usage(cmd *Command) string {
+	r.Lock()
+	defer r.Unlock()
+
+	var names []string
+	width := 0
+	for name, rt := range r.routes {
+		if rt.handler == nil {
+			continue
+		}
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage: %s <subcommand>\n\nSubcommands:\n", commandLine(cmd))
+	for _, name := range names {
+		if d := r.routes[name].description; d != "" {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, name, d)
+		} else {
+			fmt.Fprintf(&b, "  %s\n", name)
+		}
+	}
+	return b.String()
+}
+
+// commandLine returns how `cmd` was invoked up to its remaining text, such as
+// "/deploy db".
+func commandLine(cmd *Command) string {
+	return "/" + strings.Join(append([]string{cmd.Name}, cmd.Path...), " ")
+}
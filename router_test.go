@@ -0,0 +1,121 @@
+package slacker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func newDeployRouter() *slacker.Router {
+	db := slacker.NewRouter()
+	db.HandleFunc("migrate", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "migrating %s (%s)", cmd.Text, strings.Join(cmd.Path, " "))
+		return nil
+	})
+
+	r := slacker.NewRouter()
+	r.HandleFunc("start", func(w io.Writer, cmd *slacker.Command) error {
+		fmt.Fprintf(w, "starting %s", cmd.Text)
+		return nil
+	})
+	r.Describe("start", "Start a deploy")
+	r.HandleFunc("status", func(w io.Writer, cmd *slacker.Command) error {
+		io.WriteString(w, "all good")
+		return nil
+	})
+	r.Handle("db", db)
+	return r
+}
+
+func routeCommand(h slacker.Handler, text string) string {
+	var b strings.Builder
+	h.HandleCommand(&b, &slacker.Command{Name: "deploy", Text: text})
+	return b.String()
+}
+
+func TestRouterDispatchesOnFirstWord(t *testing.T) {
+	r := newDeployRouter()
+	assert.Equal(t, "starting api canary", routeCommand(r, "start api canary"))
+	assert.Equal(t, "starting api", routeCommand(r, "  START   api"))
+	assert.Equal(t, "all good", routeCommand(r, "status"))
+}
+
+func TestRouterNests(t *testing.T) {
+	r := newDeployRouter()
+	assert.Equal(t, "migrating users (db migrate)", routeCommand(r, "db migrate users"))
+}
+
+func TestRouterUsage(t *testing.T) {
+	r := newDeployRouter()
+	usage := "Usage: /deploy <subcommand>\n\nSubcommands:\n  db\n  start   Start a deploy\n  status\n"
+	assert.Equal(t, usage, routeCommand(r, ""))
+	assert.Equal(t, usage, routeCommand(r, "help"))
+	assert.Equal(t, "Unknown subcommand \"stop\".\n\n"+usage, routeCommand(r, "stop"))
+
+	nested := "Usage: /deploy db <subcommand>\n\nSubcommands:\n  migrate\n"
+	assert.Equal(t, "Unknown subcommand \"drop\".\n\n"+nested, routeCommand(r, "db drop"))
+}
+
+func TestRouterUsageIsEphemeral(t *testing.T) {
+	slack := slacker.New()
+	slack.Handle("deploy", "foo", newDeployRouter())
+
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	values := url.Values{}
+	values.Add("command", "/deploy")
+	values.Add("token", "foo")
+	values.Add("text", "stop")
+	msg := postMessage(t, server.URL, values)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.T(t, strings.HasPrefix(msg.Text, "Unknown subcommand \"stop\"."))
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	var calls []string
+	trace := func(name string) slacker.Middleware {
+		return func(next slacker.Handler) slacker.Handler {
+			return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+				calls = append(calls, name)
+				return next.HandleCommand(w, cmd)
+			})
+		}
+	}
+	deny := func(next slacker.Handler) slacker.Handler {
+		return slacker.HandlerFunc(func(w io.Writer, cmd *slacker.Command) error {
+			return fmt.Errorf("%s is not allowed", strings.Join(cmd.Path, " "))
+		})
+	}
+
+	r := slacker.NewRouter()
+	r.HandleFunc("start", func(w io.Writer, cmd *slacker.Command) error {
+		calls = append(calls, "start")
+		return nil
+	}, trace("first"), trace("second"))
+	r.HandleFunc("rollback", func(w io.Writer, cmd *slacker.Command) error {
+		calls = append(calls, "rollback")
+		return nil
+	}, deny)
+
+	err := r.HandleCommand(io.Discard, &slacker.Command{Text: "start"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"first", "second", "start"}, calls)
+
+	calls = nil
+	err = r.HandleCommand(io.Discard, &slacker.Command{Text: "rollback"})
+	assert.Equal(t, "rollback is not allowed", err.Error())
+	assert.Equal(t, 0, len(calls))
+}
+
+func TestRouterTokenizesFirstWord(t *testing.T) {
+	r := newDeployRouter()
+	assert.Equal(t, "starting “api” now", routeCommand(r, "“start” “api” now"))
+	assert.T(t, strings.HasPrefix(routeCommand(r, "“start api"), "unterminated “ quote.\n\nUsage: /deploy <subcommand>"))
+}
@@ -1,20 +1,27 @@
 package slacker
 
 import (
-	"bytes"
+	"context"
 	"crypto/subtle"
-	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Handler interface. Implementations can be registered to handle commands.
 //
 // HandleCommand should write a reply to the command and then return. An
 // appropriate user facing error should be returned if the command cannot be
-// handled.
+// handled. Handlers that take longer than Slack allows may reply immediately
+// and post delayed responses with Command.Followup.
+//
+// The writer passed to HandleCommand is a ResponseWriter, which handlers may
+// use to reply with more than plain text.
 type Handler interface {
 	HandleCommand(w io.Writer, cmd *Command) error
 }
@@ -29,105 +36,331 @@ func (h HandlerFunc) HandleCommand(w io.Writer, cmd *Command) error {
 
 // Command details sent by Slack.
 type Command struct {
-	Name        string
-	Text        string
-	Token       string
-	UserID      string
-	UserName    string
-	ChannelID   string
-	ChannelName string
+	Name                string
+	Text                string
+	Token               string
+	UserID              string
+	UserName            string
+	ChannelID           string
+	ChannelName         string
+	ResponseURL         string
+	TeamID              string
+	TeamDomain          string
+	EnterpriseID        string
+	EnterpriseName      string
+	TriggerID           string
+	APIAppID            string
+	IsEnterpriseInstall bool
+
+	// Form holds every field Slack sent, including those without a field of
+	// their own.
+	Form url.Values
+
+	// Header holds the headers of the request the command was received in. It
+	// is nil for commands received in Socket Mode.
+	Header http.Header
+
+	// Path holds the subcommands which routers have removed from the start of
+	// Text, in order.
+	Path []string
+
+	// Args holds the values captured from Text by Patterns.
+	Args map[string]interface{}
+
+	ctx       context.Context
+	responder *Responder
+	logger    Logger    // the request logger.
+	received  time.Time // when the command was received.
+}
+
+// Arg returns the string value captured as `name` in Args, or "".
+func (cmd *Command) Arg(name string) string {
+	s, _ := cmd.Args[name].(string)
+	return s
+}
+
+// Context returns the command's context, which is cancelled once the
+// command's time budget is spent. It defaults to the background context.
+func (cmd *Command) Context() context.Context {
+	if cmd.ctx == nil {
+		return context.Background()
+	}
+	return cmd.ctx
+}
+
+// WithContext returns a shallow copy of the command with its context changed
+// to `ctx`.
+func (cmd *Command) WithContext(ctx context.Context) *Command {
+	c := *cmd
+	c.ctx = ctx
+	return &c
+}
+
+// Responder returns the responder for the command's response_url, which can
+// be used to post delayed responses after the handler has returned. Commands
+// received by Slacker share a single responder so that Slack's limits are
+// tracked across posts.
+func (cmd *Command) Responder() *Responder {
+	if cmd.responder == nil {
+		return NewResponder(cmd.ResponseURL, nil)
+	}
+	return cmd.responder
+}
+
+// Followup posts `msg` to the command's response_url.
+func (cmd *Command) Followup(ctx context.Context, msg *Message) error {
+	return cmd.Responder().Post(ctx, msg)
 }
 
 // Slacker handles HTTP requests and command dispatching.
+//
+// The exported fields configure the Slacker and must not be modified once
+// requests are being served.
 type Slacker struct {
-	handlers map[string]Handler // maps a command to its handler.
-	tokens   map[string]string  // maps a command to its token.
+	// Verify selects whether requests are authenticated by verification token,
+	// by signature, or both. Defaults to VerifyToken.
+	Verify VerifyMode
+
+	// SigningSecrets are the secrets accepted when verifying signatures. More
+	// than one may be given while a secret is being rotated.
+	SigningSecrets []string
+
+	// MaxSkew is the maximum age of a signed request. Defaults to DefaultMaxSkew.
+	MaxSkew time.Duration
+
+	// Client is used to post delayed responses. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// AckTimeout is how long to wait for a handler before acknowledging the
+	// command with AckMessage and delivering the handler's output as a delayed
+	// response instead. Defaults to DefaultAckTimeout.
+	AckTimeout time.Duration
+
+	// AckMessage acknowledges commands which outlive AckTimeout. Defaults to
+	// DefaultAckMessage.
+	AckMessage string
+
+	// PanicMessage apologizes to users whose command panicked, and is formatted
+	// with the ID of the incident. Defaults to DefaultPanicMessage.
+	PanicMessage string
+
+	// ErrorMessage replaces errors returned by handlers which are not a
+	// UserError, and is formatted with the ID of the incident. Defaults to
+	// DefaultErrorMessage.
+	ErrorMessage string
+
+	// ErrorRenderer writes the replies to commands whose handler failed.
+	// Defaults to Slacker.RenderError.
+	ErrorRenderer ErrorRenderer
+
+	// Reporter is notified of incidents, such as handlers panicking.
+	Reporter Reporter
+
+	// Logger receives log records. Defaults to writing with the standard log
+	// package.
+	Logger Logger
+
+	// LogSecrets stops secrets, such as verification tokens, being redacted
+	// from log records. It should only be set while debugging.
+	LogSecrets bool
+
+	registry atomic.Pointer[registry] // the commands, read without locking.
+	patterns map[string]*Patterns     // maps a command to its patterns.
 	sync.Mutex
+
+	interactions *Interactions
+	events       *Events
+
+	ctx      context.Context    // parent of command contexts.
+	stop     context.CancelFunc // cancels ctx on shutdown.
+	inflight sync.WaitGroup     // commands still running.
 }
 
 // New slacker.
 func New() *Slacker {
-	return &Slacker{
-		handlers: make(map[string]Handler),
-		tokens:   make(map[string]string),
+	ctx, stop := context.WithCancel(context.Background())
+	s := &Slacker{
+		patterns: make(map[string]*Patterns),
+		ctx:      ctx,
+		stop:     stop,
 	}
+	s.registry.Store(newRegistry())
+	s.interactions = newInteractions(s)
+	s.events = newEvents(s)
+	return s
 }
 
-// ValidToken validates the given `token` for the given `command`.
-func (s *Slacker) ValidToken(command, token string) bool {
+// Shutdown cancels the context of every command in flight and waits for them
+// to finish, or for `ctx` to be done. Commands received afterwards are
+// rejected.
+func (s *Slacker) Shutdown(ctx context.Context) error {
 	s.Lock()
-	defer s.Unlock()
+	s.stop()
+	s.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
 
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ValidToken validates the given `token` for the given `command`.
+func (s *Slacker) ValidToken(command, token string) bool {
 	// Under normal execution, we would have already validated whether the command
 	// exists or not. But this is an exported function, so validate that it does
 	// indeed exist.
-	t, exists := s.tokens[command]
+	t, exists := s.commands().tokens[command]
 	valid := subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1
 	return exists && valid
 }
 
-// Handle registers `handler` for command `name` with `token`.
-func (s *Slacker) Handle(name, token string, handler Handler) {
-	s.handlers[name] = handler
-	s.tokens[name] = token
+// validAppToken validates the given `token` for requests which are not for a
+// particular command. Slack sends the same token for every command of an app,
+// so it is valid if it matches the token of any command.
+func (s *Slacker) validAppToken(token string) bool {
+	valid := false
+	for _, t := range s.commands().tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			valid = true
+		}
+	}
+	return valid
 }
 
-// HandleFunc registers `handler` function for command `name` with `token`.
-func (s *Slacker) HandleFunc(name, token string, handler func(io.Writer, *Command) error) {
-	s.Handle(name, token, HandlerFunc(handler))
+// Handle registers `handler` for command `name` with `token`, wrapped in
+// `mw`. Commands may be registered while requests are being served. It panics
+// if the command is already registered; use Replace to change a command's
+// handler.
+func (s *Slacker) Handle(name, token string, handler Handler, mw ...Middleware) {
+	s.HandleContext(name, token, AdaptHandler(chain(handler, mw)))
 }
 
-// ServeHTTP handles slash command requests.
+// HandleContext registers context aware `handler` for command `name` with
+// `token`. It panics if the command is already registered.
+func (s *Slacker) HandleContext(name, token string, handler ContextHandler) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.register(name, token, handler)
+}
+
+// HandleContextFunc registers context aware `handler` function for command
+// `name` with `token`.
+func (s *Slacker) HandleContextFunc(name, token string, handler func(context.Context, io.Writer, *Command) error) {
+	s.HandleContext(name, token, ContextHandlerFunc(handler))
+}
+
+// SetTimeout sets the time budget for command `name`. The command's context
+// is cancelled once the budget is spent. Defaults to DefaultTimeout.
+func (s *Slacker) SetTimeout(name string, timeout time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.update(func(r *registry) {
+		r.timeouts[name] = timeout
+	})
+}
+
+// HandleFunc registers `handler` function for command `name` with `token`,
+// wrapped in `mw`.
+func (s *Slacker) HandleFunc(name, token string, handler func(io.Writer, *Command) error, mw ...Middleware) {
+	s.Handle(name, token, HandlerFunc(handler), mw...)
+}
+
+// newCommand returns the command sent as `form` with `header`, or nil if no
+// command was sent.
+func (s *Slacker) newCommand(form url.Values, header http.Header) *Command {
+	command := form.Get("command")
+	if command == "" {
+		return nil
+	}
+
+	cmd := &Command{
+		Name:           strings.TrimPrefix(command, "/"),
+		Text:           form.Get("text"),
+		Token:          form.Get("token"),
+		UserID:         form.Get("user_id"),
+		UserName:       form.Get("user_name"),
+		ChannelID:      form.Get("channel_id"),
+		ChannelName:    form.Get("channel_name"),
+		ResponseURL:    form.Get("response_url"),
+		TeamID:         form.Get("team_id"),
+		TeamDomain:     form.Get("team_domain"),
+		EnterpriseID:   form.Get("enterprise_id"),
+		EnterpriseName: form.Get("enterprise_name"),
+		TriggerID:      form.Get("trigger_id"),
+		APIAppID:       form.Get("api_app_id"),
+		Form:           form,
+		Header:         header,
+	}
+	cmd.IsEnterpriseInstall, _ = strconv.ParseBool(form.Get("is_enterprise_install"))
+	cmd.responder = NewResponder(cmd.ResponseURL, s.Client)
+	return cmd
+}
+
+// ServeHTTP handles slash command requests. The request ID in their logs is
+// taken from the X-Request-Id header when a proxy has set one.
 func (s *Slacker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseForm()
+	err := s.verifySignature(r)
+	if err != nil {
+		s.logger().Error("verifying signature", "err", err)
+		http.Error(w, "Invalid signature", 401)
+		return
+	}
+
+	err = r.ParseForm()
 	if err != nil {
-		log.Printf("[error] parsing form: %s", err)
+		s.logger().Error("parsing form", "err", err)
 		http.Error(w, "Invalid request body", 400)
 		return
 	}
 
-	command := r.Form.Get("command")
+	// Signatures cover only the body, so query parameters are ignored when
+	// they are checked.
+	form := r.Form
+	if s.Verify.checksSignature() {
+		form = r.PostForm
+	}
 
-	if command == "" {
+	cmd := s.newCommand(form, r.Header)
+	if cmd == nil {
 		http.Error(w, "command required", 400)
 		return
 	}
+	s.track(cmd, r.Header.Get("X-Request-Id"))
+	log := cmd.Logger()
 
-	cmd := &Command{
-		Name:        command[1:],
-		Text:        r.Form.Get("text"),
-		Token:       r.Form.Get("token"),
-		UserID:      r.Form.Get("user_id"),
-		UserName:    r.Form.Get("user_name"),
-		ChannelID:   r.Form.Get("channel_id"),
-		ChannelName: r.Form.Get("channel_name"),
-	}
-
-	h, ok := s.handlers[cmd.Name]
+	h, ok := s.handler(cmd.Name)
 	if !ok {
-		log.Printf("[error] invalid command %q", cmd.Name)
+		log.Error("invalid command")
 		http.Error(w, "Invalid command", 400)
 		return
 	}
 
-	if !s.ValidToken(cmd.Name, cmd.Token) {
-		log.Printf("[error] invalid token %q for command %q", cmd.Token, cmd.Name)
-		http.Error(w, fmt.Sprintf("Invalid token %q for command %q", cmd.Token, cmd.Name), 401)
+	if s.Verify.checksToken() && !s.ValidToken(cmd.Name, cmd.Token) {
+		log.Error("invalid token")
+		http.Error(w, "Invalid token", 401)
 		return
 	}
 
-	log.Printf("[info] received %s %q from %s in %s", cmd.Name, cmd.Text, cmd.UserName, cmd.ChannelName)
+	log.Info("received command", "text", cmd.Text, "channel", cmd.ChannelID)
 
-	var buf bytes.Buffer
-	err = h.HandleCommand(&buf, cmd)
-	if err != nil {
-		log.Printf("[error] handling command: %s", err)
-		http.Error(w, err.Error(), 500)
+	res := s.run(w, r, h, cmd)
+	if res == nil {
 		return
 	}
 
-	_, err = io.Copy(w, &buf)
+	msg := s.reply(r.Context(), cmd, res)
+	err = writeMessage(w, msg)
 	if err != nil {
-		log.Printf("[error] writing: %s", err)
+		log.Error("writing reply", "err", err)
 	}
 }
@@ -1,13 +1,14 @@
 package slacker_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-
+	"strings"
 	"testing"
 
 	"github.com/bmizerany/assert"
@@ -53,6 +54,50 @@ func TestParsesForm(t *testing.T) {
 	assert.Equal(t, "d", cmd.ChannelName)
 }
 
+func TestParsesFullPayload(t *testing.T) {
+	slack := slacker.New()
+	cmds := make(chan *slacker.Command, 1)
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		cmds <- cmd
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+	values.Add("response_url", "https://hooks.slack.com/commands/1234/5678")
+	values.Add("team_id", "T1")
+	values.Add("team_domain", "example")
+	values.Add("enterprise_id", "E1")
+	values.Add("enterprise_name", "Example Corp")
+	values.Add("trigger_id", "13345224609.738474920.8088930838d88f008e0")
+	values.Add("api_app_id", "A1")
+	values.Add("is_enterprise_install", "true")
+	values.Add("channel_type", "private")
+
+	req, err := http.NewRequest("POST", ts.URL, strings.NewReader(values.Encode()))
+	assert.Equal(t, nil, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", "1531420618")
+	res, err := http.DefaultClient.Do(req)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	cmd := <-cmds
+	assert.Equal(t, "https://hooks.slack.com/commands/1234/5678", cmd.ResponseURL)
+	assert.Equal(t, "T1", cmd.TeamID)
+	assert.Equal(t, "example", cmd.TeamDomain)
+	assert.Equal(t, "E1", cmd.EnterpriseID)
+	assert.Equal(t, "Example Corp", cmd.EnterpriseName)
+	assert.Equal(t, "13345224609.738474920.8088930838d88f008e0", cmd.TriggerID)
+	assert.Equal(t, "A1", cmd.APIAppID)
+	assert.Equal(t, true, cmd.IsEnterpriseInstall)
+	assert.Equal(t, "private", cmd.Form.Get("channel_type"))
+	assert.Equal(t, "1531420618", cmd.Header.Get("X-Slack-Request-Timestamp"))
+}
+
 // Make a post request to the given url with the given values and verify the response code and body.
 func testResponse(t *testing.T, url string, values url.Values, expectedStatus int, expectedBody string) {
 	resp, err := http.PostForm(url, values)
@@ -67,6 +112,36 @@ func testResponse(t *testing.T, url string, values url.Values, expectedStatus in
 	assert.Equal(t, expectedBody+"\n", string(body))
 }
 
+// Make a post request to the given url with the given values and return the command's reply.
+func postMessage(t *testing.T, url string, values url.Values) *slacker.Message {
+	resp, err := http.PostForm(url, values)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var msg slacker.Message
+	err = json.NewDecoder(resp.Body).Decode(&msg)
+	if err != nil {
+		t.Fatalf("could not decode reply with error: %s", err)
+	}
+	return &msg
+}
+
+// Post command `name` with the given text and the token "foo" to the given url and return the command's reply.
+func postCommand(t *testing.T, server, name, text string) *slacker.Message {
+	values := url.Values{}
+	values.Add("command", "/"+name)
+	values.Add("token", "foo")
+	values.Add("text", text)
+	return postMessage(t, server, values)
+}
+
+// Make a post request to the given url with the given values and verify the text of the command's reply.
+func testReply(t *testing.T, url string, values url.Values, expectedText string) {
+	assert.Equal(t, expectedText, postMessage(t, url, values).Text)
+}
+
 func TestCommandIsRequired(t *testing.T) {
 	slack := slacker.New()
 	ts := httptest.NewServer(slack)
@@ -101,7 +176,7 @@ func TestFailsForInvalidToken(t *testing.T) {
 	values.Add("command", "/hello")
 	values.Add("token", "non-foo")
 
-	testResponse(t, ts.URL, values, 401, "Invalid token \"non-foo\" for command \"hello\"")
+	testResponse(t, ts.URL, values, 401, "Invalid token")
 }
 
 func TestFailsWhenHandlerErrors(t *testing.T) {
@@ -116,7 +191,11 @@ func TestFailsWhenHandlerErrors(t *testing.T) {
 	values.Add("command", "/hello")
 	values.Add("token", "foo")
 
-	testResponse(t, ts.URL, values, 500, "test error")
+	// The error is not shown, since it was not meant for the user.
+	msg := postMessage(t, ts.URL, values)
+	assert.Equal(t, slacker.Ephemeral, msg.ResponseType)
+	assert.T(t, strings.HasPrefix(msg.Text, "Sorry, that didn't work. If it keeps happening, please report incident "))
+	assert.T(t, !strings.Contains(msg.Text, "test error"))
 }
 
 func TestValidatesToken(t *testing.T) {
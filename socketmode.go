@@ -0,0 +1,326 @@
+package slacker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Socket Mode envelope types.
+const (
+	envelopeHello         = "hello"
+	envelopeDisconnect    = "disconnect"
+	envelopeSlashCommands = "slash_commands"
+	envelopeInteractive   = "interactive"
+	envelopeEventsAPI     = "events_api"
+)
+
+// Defaults for reconnecting in Socket Mode.
+const (
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// DefaultReadTimeout is how long a Socket Mode connection may be silent. Slack
+// pings connections regularly, so a silent connection is most likely
+// half-open.
+const DefaultReadTimeout = 30 * time.Second
+
+// maxMessageSize bounds the size of a received message.
+const maxMessageSize = 16 << 20
+
+// errDisconnect is returned when Slack asks for the connection to be
+// replaced.
+var errDisconnect = errors.New("slacker: disconnect requested")
+
+// envelope is a message received in Socket Mode.
+type envelope struct {
+	Type        string          `json:"type"`
+	EnvelopeID  string          `json:"envelope_id"`
+	Payload     json.RawMessage `json:"payload"`
+	Reason      string          `json:"reason"`
+	RetryNum    int             `json:"retry_attempt"`
+	RetryReason string          `json:"retry_reason"`
+}
+
+// ack acknowledges an envelope, with an optional payload.
+type ack struct {
+	EnvelopeID string      `json:"envelope_id"`
+	Payload    interface{} `json:"payload,omitempty"`
+}
+
+// SocketMode receives commands, interactions and events over a WebSocket
+// instead of HTTP requests, for apps which cannot expose a public request URL.
+// Everything received is dispatched to the handlers registered with the
+// Slacker, its Interactions and its Events.
+type SocketMode struct {
+	// API opens connections, and must hold an app level token with the
+	// connections:write scope.
+	API *API
+
+	// MinBackoff and MaxBackoff bound the delay before reconnecting after a
+	// failure. They default to DefaultMinBackoff and DefaultMaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// ReadTimeout is how long a connection may go without receiving anything,
+	// including pings, before it is considered failed and reopened. Defaults
+	// to DefaultReadTimeout.
+	ReadTimeout time.Duration
+
+	slacker *Slacker
+}
+
+// socketConn is a Socket Mode connection. Acks are written by the goroutines
+// dispatching envelopes, so writes are serialized.
+type socketConn struct {
+	*websocket.Conn
+	mu sync.Mutex // serializes writes.
+}
+
+// write sends `b` as a text message.
+func (c *socketConn) write(b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.WriteMessage(websocket.TextMessage, b)
+}
+
+// NewSocketMode returns a Socket Mode client for `s`, which opens
+// connections with `api`.
+func NewSocketMode(s *Slacker, api *API) *SocketMode {
+	return &SocketMode{API: api, slacker: s}
+}
+
+// Run connects to Slack and dispatches what it receives until `ctx` is done.
+// The connection is replaced when Slack asks for it to be, and reopened with
+// backoff when it fails or has been silent for longer than the ReadTimeout.
+func (m *SocketMode) Run(ctx context.Context) error {
+	minBackoff := m.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinBackoff
+	}
+	maxBackoff := m.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	backoff := minBackoff
+	for {
+		healthy, err := m.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if healthy {
+			backoff = minBackoff
+		}
+		if err == errDisconnect {
+			m.slacker.logger().Info("reconnecting to socket mode")
+			continue
+		}
+
+		m.slacker.logger().Error("socket mode connection", "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connect opens a connection and serves it until it fails or Slack asks for
+// it to be replaced. It reports whether the connection was established.
+func (m *SocketMode) connect(ctx context.Context) (bool, error) {
+	var open struct {
+		URL string `json:"url"`
+	}
+	err := m.API.Call(ctx, "apps.connections.open", struct{}{}, &open)
+	if err != nil {
+		return false, err
+	}
+
+	dialer := &websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	c, _, err := dialer.DialContext(ctx, open.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	conn := &socketConn{Conn: c}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// Every frame received, including pings, extends the read deadline, so
+	// that a connection which has silently gone away fails rather than
+	// blocking forever.
+	timeout := m.ReadTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadTimeout
+	}
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		return err
+	})
+
+	healthy := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return healthy, err
+		}
+
+		env := &envelope{}
+		err = json.Unmarshal(b, env)
+		if err != nil {
+			m.slacker.logger().Error("parsing envelope", "err", err)
+			continue
+		}
+
+		switch env.Type {
+		case envelopeHello:
+			healthy = true
+		case envelopeDisconnect:
+			m.slacker.logger().Info("socket mode disconnect", "reason", env.Reason)
+			return healthy, errDisconnect
+		default:
+			// Envelopes which arrive during Shutdown are not acknowledged, so
+			// that Slack delivers them again.
+			err = m.slacker.background(ctx, func(ctx context.Context) {
+				m.dispatch(ctx, conn, env)
+			})
+			if err != nil {
+				m.slacker.logger().Error("handling envelope", "type", env.Type, "envelope_id", env.EnvelopeID, "err", err)
+			}
+		}
+	}
+}
+
+// dispatch handles an envelope and acknowledges it. A panic is recovered,
+// since handlers run in the background, and reported as an incident.
+func (m *SocketMode) dispatch(ctx context.Context, conn *socketConn, env *envelope) {
+	defer func() {
+		v := recover()
+		if v != nil {
+			m.slacker.panicked(ctx, &Incident{}, env.Type+" envelope", v)
+		}
+	}()
+
+	// Events are acknowledged first, since Slack redelivers events which are
+	// not acknowledged promptly and their handlers may take a while.
+	if env.Type == envelopeEventsAPI {
+		m.ack(conn, env, nil)
+	}
+
+	payload, err := m.handle(ctx, env)
+	if err != nil {
+		m.slacker.logger().Error("handling envelope", "type", env.Type, "envelope_id", env.EnvelopeID, "err", err)
+	}
+
+	if env.Type != envelopeEventsAPI {
+		m.ack(conn, env, payload)
+	}
+}
+
+// handle dispatches an envelope to its handlers, and returns the payload to
+// acknowledge it with, if any.
+func (m *SocketMode) handle(ctx context.Context, env *envelope) (interface{}, error) {
+	s := m.slacker
+
+	switch env.Type {
+	case envelopeSlashCommands:
+		var fields map[string]interface{}
+		err := json.Unmarshal(env.Payload, &fields)
+		if err != nil {
+			return nil, err
+		}
+		form := url.Values{}
+		for k, v := range fields {
+			form.Set(k, fmt.Sprint(v))
+		}
+
+		cmd := s.newCommand(form, nil)
+		if cmd == nil {
+			return nil, errors.New("command required")
+		}
+		h, ok := s.handler(cmd.Name)
+		if !ok {
+			return nil, fmt.Errorf("invalid command %q", cmd.Name)
+		}
+		s.track(cmd, env.EnvelopeID)
+
+		cmd.Logger().Info("received command", "text", cmd.Text, "channel", cmd.ChannelID)
+
+		res, err := s.await(ctx, h, cmd)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return &Message{Text: s.ackMessage()}, nil
+		}
+
+		return s.reply(ctx, cmd, res), nil
+
+	case envelopeInteractive:
+		in := &Interaction{}
+		err := json.Unmarshal(env.Payload, in)
+		if err != nil {
+			return nil, err
+		}
+		// Actions are acknowledged before they are handled, as they are
+		// over HTTP.
+		if in.Type == BlockActions {
+			return nil, s.background(ctx, func(ctx context.Context) {
+				s.interactions.serve(ctx, in)
+			})
+		}
+		reply := s.interactions.serve(ctx, in)
+		if reply == nil {
+			return nil, nil
+		}
+		return reply, nil
+
+	case envelopeEventsAPI:
+		ev := &EventEnvelope{}
+		err := json.Unmarshal(env.Payload, ev)
+		if err != nil {
+			return nil, err
+		}
+		s.events.handle(ctx, ev, env.RetryNum, env.RetryReason)
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported envelope %q", env.Type)
+	}
+}
+
+// ack acknowledges `env` with `payload`.
+func (m *SocketMode) ack(conn *socketConn, env *envelope, payload interface{}) {
+	if msg, ok := payload.(*Message); ok && msg.empty() {
+		payload = nil
+	}
+
+	b, err := json.Marshal(&ack{EnvelopeID: env.EnvelopeID, Payload: payload})
+	if err != nil {
+		m.slacker.logger().Error("encoding ack", "err", err)
+		return
+	}
+	err = conn.write(b)
+	if err != nil {
+		m.slacker.logger().Error("writing ack", "err", err)
+	}
+}
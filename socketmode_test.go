@@ -0,0 +1,216 @@
+package slacker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/gorilla/websocket"
+	"github.com/segmentio/go-slacker"
+)
+
+// socketServer stands in for Slack in Socket Mode. Each connection is passed
+// to `conns` once the hello has been sent.
+type socketServer struct {
+	*httptest.Server
+	conns chan *websocket.Conn
+
+	sync.Mutex
+	opens int
+}
+
+func newSocketServer() *socketServer {
+	s := &socketServer{conns: make(chan *websocket.Conn)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		s.Lock()
+		s.opens++
+		s.Unlock()
+		url := "ws" + strings.TrimPrefix(s.URL, "http") + "/link"
+		fmt.Fprintf(w, `{"ok":true,"url":%q}`, url)
+	})
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello"}`))
+		s.conns <- conn
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Send `env` over `conn` and return the ack.
+func roundTrip(t *testing.T, conn *websocket.Conn, env string) map[string]interface{} {
+	err := conn.WriteMessage(websocket.TextMessage, []byte(env))
+	if err != nil {
+		t.Fatalf("could not write envelope with error: %s", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, b, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("could not read ack with error: %s", err)
+	}
+	var ack map[string]interface{}
+	json.Unmarshal(b, &ack)
+	return ack
+}
+
+func TestSocketMode(t *testing.T) {
+	server := newSocketServer()
+	defer server.Close()
+
+	events := make(chan *slacker.Event, 1)
+	slack := slacker.New()
+	slack.HandleFunc("deploy", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).SetResponseType(slacker.InChannel)
+		fmt.Fprintf(w, "Deploying %s", cmd.Text)
+		return nil
+	})
+	slack.Interactions().HandleViewFunc("deploy", func(ctx context.Context, w slacker.ResponseWriter, in *slacker.Interaction) error {
+		return slacker.ViewErrors{"app": "App is required"}
+	})
+	slack.Events().HandleEventFunc(slacker.EventAppMention, func(ctx context.Context, ev *slacker.Event) error {
+		events <- ev
+		return nil
+	})
+
+	mode := slacker.NewSocketMode(slack, &slacker.API{Token: "xapp-token", URL: server.URL + "/"})
+	mode.MinBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mode.Run(ctx)
+	}()
+
+	conn := <-server.conns
+	ack := roundTrip(t, conn, `{"type":"slash_commands","envelope_id":"1","payload":{"command":"/deploy","text":"api","user_id":"U1"}}`)
+	assert.Equal(t, map[string]interface{}{
+		"envelope_id": "1",
+		"payload":     map[string]interface{}{"text": "Deploying api", "response_type": "in_channel"},
+	}, ack)
+
+	ack = roundTrip(t, conn, `{"type":"interactive","envelope_id":"2","payload":{"type":"view_submission","view":{"callback_id":"deploy"}}}`)
+	assert.Equal(t, map[string]interface{}{
+		"envelope_id": "2",
+		"payload":     map[string]interface{}{"response_action": "errors", "errors": map[string]interface{}{"app": "App is required"}},
+	}, ack)
+
+	// Slack asks for the connection to be replaced.
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"disconnect","reason":"refresh_requested"}`))
+	conn = <-server.conns
+
+	ack = roundTrip(t, conn, `{"type":"events_api","envelope_id":"3","payload":{"type":"event_callback","event":{"type":"app_mention","text":"hello"}}}`)
+	assert.Equal(t, map[string]interface{}{"envelope_id": "3"}, ack)
+	ev := <-events
+	assert.Equal(t, "hello", ev.Data.(*slacker.AppMentionEvent).Text)
+
+	// The connection is reopened when it fails.
+	conn.Close()
+	conn = <-server.conns
+
+	server.Lock()
+	assert.Equal(t, 3, server.opens)
+	server.Unlock()
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	conn.Close()
+}
+
+func TestSocketModeReopensSilentConnections(t *testing.T) {
+	server := newSocketServer()
+	defer server.Close()
+
+	mode := slacker.NewSocketMode(slacker.New(), &slacker.API{Token: "xapp-token", URL: server.URL + "/"})
+	mode.MinBackoff = time.Millisecond
+	mode.ReadTimeout = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mode.Run(ctx)
+	}()
+
+	// Pings keep the connection open.
+	conn := <-server.conns
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	for i := 0; i < 15; i++ {
+		conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+		time.Sleep(20 * time.Millisecond)
+	}
+	server.Lock()
+	assert.Equal(t, 1, server.opens)
+	server.Unlock()
+
+	// A connection which goes silent is reopened.
+	select {
+	case next := <-server.conns:
+		next.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("silent connection was not reopened")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	conn.Close()
+}
+
+func TestSocketModeRecoversPanicsAndShutsDown(t *testing.T) {
+	server := newSocketServer()
+	defer server.Close()
+
+	incidents := make(chan *slacker.Incident, 1)
+	started := make(chan struct{})
+	finished := false
+	slack := slacker.New()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		incidents <- incident
+	})
+	slack.Events().HandleEventFunc("team_join", func(ctx context.Context, ev *slacker.Event) error {
+		panic("boom")
+	})
+	slack.Events().HandleEventFunc(slacker.EventAppMention, func(ctx context.Context, ev *slacker.Event) error {
+		close(started)
+		<-ctx.Done()
+		finished = true
+		return nil
+	})
+
+	mode := slacker.NewSocketMode(slack, &slacker.API{Token: "xapp-token", URL: server.URL + "/"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mode.Run(ctx)
+
+	conn := <-server.conns
+	defer conn.Close()
+	ack := roundTrip(t, conn, `{"type":"events_api","envelope_id":"1","payload":{"type":"event_callback","event":{"type":"team_join"}}}`)
+	assert.Equal(t, map[string]interface{}{"envelope_id": "1"}, ack)
+	incident := <-incidents
+	assert.Equal(t, "panic handling event team_join: boom", incident.Err.Error())
+
+	roundTrip(t, conn, `{"type":"events_api","envelope_id":"2","payload":{"type":"event_callback","event":{"type":"app_mention"}}}`)
+	<-started
+
+	// Shutdown cancels the handler and waits for it.
+	shutdown, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	assert.Equal(t, nil, slack.Shutdown(shutdown))
+	assert.T(t, finished)
+}
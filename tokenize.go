@@ -0,0 +1,216 @@
+package slacker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a word of a command's text.
+type Token struct {
+	// Text is the word with its quotes and escapes removed and HTML entities
+	// decoded. Slack entities such as "<@U123|bob>" are kept as they were sent.
+	Text string
+
+	// Start and End are the byte offsets of the word in the source text,
+	// including any quotes.
+	Start, End int
+
+	// Quoted reports whether any part of the word was quoted or escaped, so
+	// that, for example, a quoted "--force" is not taken for a flag.
+	Quoted bool
+}
+
+// TokenError reports text which cannot be tokenized.
+type TokenError struct {
+	Offset  int // byte offset of the problem in the source text.
+	Message string
+}
+
+// Error implements error.
+func (e *TokenError) Error() string {
+	return e.Message
+}
+
+// Tokenize splits `text` into words the way a shell would, while undoing what
+// Slack does to the text of commands. Words are separated by whitespace, and
+// may be quoted with straight or smart quotes (“ ” ‘ ’) or escaped with
+// backslashes. Single quotes only open a quote at the start of a word, and ’
+// never does, so apostrophes need no escaping. The HTML entities Slack
+// escapes &, < and > with are decoded, and Slack entities such as links and
+// mentions are kept whole. It returns a TokenError if a quote or entity is
+// not terminated.
+func Tokenize(text string) ([]Token, error) {
+	var tokens []Token
+	var word strings.Builder
+	tok := Token{Start: -1}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			if tok.Start >= 0 {
+				tok.Text, tok.End = word.String(), i
+				tokens = append(tokens, tok)
+				word.Reset()
+				tok = Token{Start: -1}
+			}
+			i += size
+			continue
+
+		case tok.Start < 0:
+			tok.Start = i
+		}
+
+		switch {
+		case r == '\\' && i+size < len(text):
+			next, n := utf8.DecodeRuneInString(text[i+size:])
+			word.WriteRune(next)
+			tok.Quoted = true
+			i += size + n
+
+		case r == '<':
+			end := strings.IndexByte(text[i:], '>')
+			if end < 0 {
+				return nil, &TokenError{Offset: i, Message: "unterminated <"}
+			}
+			word.WriteString(text[i : i+end+1])
+			i += end + 1
+
+		case r == '&':
+			c, n := decodeEntity(text[i:])
+			word.WriteString(c)
+			i += n
+
+		case isDoubleQuote(r) || (opensSingleQuote(r) && i == tok.Start):
+			end, err := readQuoted(&word, text, i, r)
+			if err != nil {
+				return nil, err
+			}
+			tok.Quoted = true
+			i = end
+
+		default:
+			word.WriteRune(r)
+			i += size
+		}
+	}
+
+	if tok.Start >= 0 {
+		tok.Text, tok.End = word.String(), len(text)
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// readQuoted writes the quoted text starting with `quote` at `start` to
+// `word`, and returns the offset following the closing quote.
+func readQuoted(word *strings.Builder, text string, start int, quote rune) (int, error) {
+	double := isDoubleQuote(quote)
+	i := start + utf8.RuneLen(quote)
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case double && isDoubleQuote(r):
+			return i + size, nil
+
+		// Single quotes are also apostrophes, so they only close a quote at
+		// the end of a word.
+		case !double && isSingleQuote(r) && endsWord(text, i+size):
+			return i + size, nil
+
+		case double && r == '\\' && i+size < len(text):
+			next, n := utf8.DecodeRuneInString(text[i+size:])
+			word.WriteRune(next)
+			i += size + n
+
+		case r == '<':
+			end := strings.IndexByte(text[i:], '>')
+			if end < 0 {
+				return 0, &TokenError{Offset: i, Message: "unterminated <"}
+			}
+			word.WriteString(text[i : i+end+1])
+			i += end + 1
+
+		case r == '&':
+			c, n := decodeEntity(text[i:])
+			word.WriteString(c)
+			i += n
+
+		default:
+			word.WriteRune(r)
+			i += size
+		}
+	}
+	return 0, &TokenError{Offset: start, Message: "unterminated " + string(quote) + " quote"}
+}
+
+// entities Slack escapes in the text of commands.
+var entities = []struct {
+	entity, char string
+}{
+	{"&amp;", "&"},
+	{"&lt;", "<"},
+	{"&gt;", ">"},
+}
+
+// decodeEntity decodes the entity at the start of `text`, and returns it with
+// the number of bytes it took. A lone "&" decodes as itself.
+func decodeEntity(text string) (string, int) {
+	for _, e := range entities {
+		if strings.HasPrefix(text, e.entity) {
+			return e.char, len(e.entity)
+		}
+	}
+	return "&", 1
+}
+
+// unescapeEntities decodes the entities Slack escapes in `text`.
+func unescapeEntities(text string) string {
+	if !strings.Contains(text, "&") {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		if text[i] != '&' {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		c, n := decodeEntity(text[i:])
+		b.WriteString(c)
+		i += n
+	}
+	return b.String()
+}
+
+// endsWord reports whether a word ends at offset `i` of `text`.
+func endsWord(text string, i int) bool {
+	if i == len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsSpace(r)
+}
+
+// isDoubleQuote reports whether `r` is a straight or smart double quote.
+func isDoubleQuote(r rune) bool {
+	return r == '"' || r == '“' || r == '”'
+}
+
+// isSingleQuote reports whether `r` is a straight or smart single quote.
+func isSingleQuote(r rune) bool {
+	return r == '\'' || r == '‘' || r == '’'
+}
+
+// opensSingleQuote reports whether `r` may open a single quote. A right
+// quote is an apostrophe at the start of a word, as in ’90s.
+func opensSingleQuote(r rune) bool {
+	return r == '\'' || r == '‘'
+}
+
+// Tokens returns the words of the command's text, as split by Tokenize.
+func (cmd *Command) Tokens() ([]Token, error) {
+	return Tokenize(cmd.Text)
+}
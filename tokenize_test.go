@@ -0,0 +1,75 @@
+package slacker_test
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+func tokenTexts(tokens []slacker.Token) []string {
+	texts := []string{}
+	for _, tok := range tokens {
+		texts = append(texts, tok.Text)
+	}
+	return texts
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"  deploy   api\tnow \n", []string{"deploy", "api", "now"}},
+		{`say "hello world"`, []string{"say", "hello world"}},
+		{"say “hello world” ‘and you’", []string{"say", "hello world", "and you"}},
+		{`say 'it's fine' don't`, []string{"say", "it's fine", "don't"}},
+		{"say don’t “it’s”", []string{"say", "don’t", "it’s"}},
+		{"’90s rock", []string{"’90s", "rock"}},
+		{`say hello\ world \"hi\" "a \"b\""`, []string{"say", "hello world", `"hi"`, `a "b"`}},
+		{`say 'a \ b'`, []string{"say", `a \ b`}},
+		{"say a&amp;b &lt;tag&gt; &nbsp;", []string{"say", "a&b", "<tag>", "&nbsp;"}},
+		{"ping <@U123|bob> in <#C123|general> <https://x.com?a=1&amp;b=2|a link>", []string{"ping", "<@U123|bob>", "in", "<#C123|general>", "<https://x.com?a=1&amp;b=2|a link>"}},
+		{`say "hi <@U123> &amp; co"`, []string{"say", "hi <@U123> & co"}},
+		{`say pre"fix"ed ""`, []string{"say", "prefixed", ""}},
+	}
+	for _, test := range tests {
+		tokens, err := slacker.Tokenize(test.text)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, test.expected, tokenTexts(tokens))
+	}
+}
+
+func TestTokenOffsets(t *testing.T) {
+	text := `deploy “web app” --force`
+	tokens, err := slacker.Tokenize(text)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(tokens))
+
+	assert.Equal(t, "deploy", text[tokens[0].Start:tokens[0].End])
+	assert.Equal(t, "“web app”", text[tokens[1].Start:tokens[1].End])
+	assert.Equal(t, "--force", text[tokens[2].Start:tokens[2].End])
+	assert.Equal(t, false, tokens[0].Quoted)
+	assert.Equal(t, true, tokens[1].Quoted)
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		text    string
+		offset  int
+		message string
+	}{
+		{`say "hello`, 4, "unterminated \" quote"},
+		{"say “hello", 4, "unterminated “ quote"},
+		{"say 'it's", 4, "unterminated ' quote"},
+		{"ping <@U123", 5, "unterminated <"},
+	}
+	for _, test := range tests {
+		_, err := slacker.Tokenize(test.text)
+		terr, ok := err.(*slacker.TokenError)
+		assert.T(t, ok)
+		assert.Equal(t, test.offset, terr.Offset)
+		assert.Equal(t, test.message, terr.Error())
+	}
+}
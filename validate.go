@@ -0,0 +1,247 @@
+package slacker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits Slack places on messages and their blocks.
+const (
+	MaxBlocks          = 50
+	MaxTextLength      = 40000
+	MaxSectionText     = 3000
+	MaxSectionFields   = 10
+	MaxFieldText       = 2000
+	MaxHeaderText      = 150
+	MaxActionsElements = 25
+	MaxContextElements = 10
+	MaxIDLength        = 255
+	MaxButtonText      = 75
+	MaxButtonValue     = 2000
+	MaxURLLength       = 3000
+	MaxAltText         = 2000
+	MaxOptions         = 100
+	MaxOverflowOptions = 5
+)
+
+// ValidationError describes a problem with part of a message.
+type ValidationError struct {
+	Path    string // such as "blocks[3].elements[0].action_id".
+	Message string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the problems found with a message.
+type ValidationErrors []*ValidationError
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "slacker: invalid message: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the message against the limits Slack enforces, which are
+// otherwise reported only as a generic error when the message is sent. It
+// returns ValidationErrors describing every problem found, or nil.
+func (m *Message) Validate() error {
+	v := &validator{blockIDs: make(map[string]string)}
+
+	if n := utf8.RuneCountInString(m.Text); n > MaxTextLength {
+		v.errorf("text", "has %d characters, more than %d", n, MaxTextLength)
+	}
+
+	if len(m.Blocks) > MaxBlocks {
+		v.errorf("blocks", "has %d blocks, more than %d", len(m.Blocks), MaxBlocks)
+	}
+
+	// Blocks may be any value which marshals to a block, so they are checked
+	// in their generic JSON form.
+	for i, block := range m.Blocks {
+		path := fmt.Sprintf("blocks[%d]", i)
+		b, err := json.Marshal(block)
+		if err != nil {
+			v.errorf(path, "cannot be marshalled: %s", err)
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			v.errorf(path, "is not an object")
+			continue
+		}
+		// Action IDs need only be unique within their block.
+		v.actionIDs = make(map[string]string)
+		v.block(path, obj)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// validator accumulates the errors found in a message.
+type validator struct {
+	errs      ValidationErrors
+	blockIDs  map[string]string // maps a block_id to where it was first seen.
+	actionIDs map[string]string // maps an action_id to where it was seen in the block.
+}
+
+// errorf records an error at `path`.
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// block checks a layout block.
+func (v *validator) block(path string, b map[string]interface{}) {
+	if id, ok := b["block_id"].(string); ok {
+		v.id(path+".block_id", id, v.blockIDs)
+	}
+
+	switch typ := str(b, "type"); typ {
+	case "section":
+		if text, ok := b["text"].(map[string]interface{}); ok {
+			v.text(path+".text", text, MaxSectionText)
+		} else if _, ok := b["fields"]; !ok {
+			v.errorf(path, "has neither text nor fields")
+		}
+		fields := list(b, "fields")
+		if len(fields) > MaxSectionFields {
+			v.errorf(path+".fields", "has %d fields, more than %d", len(fields), MaxSectionFields)
+		}
+		for i, f := range fields {
+			if field, ok := f.(map[string]interface{}); ok {
+				v.text(fmt.Sprintf("%s.fields[%d]", path, i), field, MaxFieldText)
+			}
+		}
+		if accessory, ok := b["accessory"].(map[string]interface{}); ok {
+			v.element(path+".accessory", accessory)
+		}
+	case "actions":
+		v.elements(path, b, MaxActionsElements)
+	case "context":
+		v.elements(path, b, MaxContextElements)
+	case "header":
+		if text, ok := b["text"].(map[string]interface{}); ok {
+			v.text(path+".text", text, MaxHeaderText)
+		} else {
+			v.errorf(path+".text", "is required")
+		}
+	case "image":
+		v.image(path, b)
+	case "input":
+		if element, ok := b["element"].(map[string]interface{}); ok {
+			v.element(path+".element", element)
+		} else {
+			v.errorf(path+".element", "is required")
+		}
+	case "divider", "rich_text", "file", "video":
+	case "":
+		v.errorf(path+".type", "is required")
+	default:
+		v.errorf(path+".type", "unknown block type %q", typ)
+	}
+}
+
+// elements checks the elements of an actions or context block.
+func (v *validator) elements(path string, b map[string]interface{}, max int) {
+	elements := list(b, "elements")
+	if len(elements) == 0 {
+		v.errorf(path+".elements", "is empty")
+	}
+	if len(elements) > max {
+		v.errorf(path+".elements", "has %d elements, more than %d", len(elements), max)
+	}
+	for i, e := range elements {
+		if element, ok := e.(map[string]interface{}); ok {
+			v.element(fmt.Sprintf("%s.elements[%d]", path, i), element)
+		}
+	}
+}
+
+// element checks a block element.
+func (v *validator) element(path string, e map[string]interface{}) {
+	if id, ok := e["action_id"].(string); ok {
+		v.id(path+".action_id", id, v.actionIDs)
+	}
+
+	switch str(e, "type") {
+	case "button":
+		if text, ok := e["text"].(map[string]interface{}); ok {
+			v.text(path+".text", text, MaxButtonText)
+		} else {
+			v.errorf(path+".text", "is required")
+		}
+		v.length(path+".value", str(e, "value"), MaxButtonValue)
+		v.length(path+".url", str(e, "url"), MaxURLLength)
+	case "static_select", "multi_static_select":
+		options := list(e, "options")
+		if len(options) > MaxOptions {
+			v.errorf(path+".options", "has %d options, more than %d", len(options), MaxOptions)
+		}
+	case "overflow":
+		options := list(e, "options")
+		if len(options) < 2 || len(options) > MaxOverflowOptions {
+			v.errorf(path+".options", "has %d options, not between 2 and %d", len(options), MaxOverflowOptions)
+		}
+	case "image":
+		v.image(path, e)
+	case "":
+		v.errorf(path+".type", "is required")
+	}
+}
+
+// image checks an image block or element.
+func (v *validator) image(path string, i map[string]interface{}) {
+	if str(i, "image_url") == "" {
+		v.errorf(path+".image_url", "is required")
+	}
+	v.length(path+".image_url", str(i, "image_url"), MaxURLLength)
+	v.length(path+".alt_text", str(i, "alt_text"), MaxAltText)
+}
+
+// text checks a text object.
+func (v *validator) text(path string, t map[string]interface{}, max int) {
+	text := str(t, "text")
+	if text == "" {
+		v.errorf(path+".text", "is empty")
+	}
+	v.length(path+".text", text, max)
+}
+
+// length checks that `s` has at most `max` characters.
+func (v *validator) length(path, s string, max int) {
+	if n := utf8.RuneCountInString(s); n > max {
+		v.errorf(path, "has %d characters, more than %d", n, max)
+	}
+}
+
+// id checks that `id` is short enough and unique among `seen`.
+func (v *validator) id(path, id string, seen map[string]string) {
+	v.length(path, id, MaxIDLength)
+	if first, ok := seen[id]; ok {
+		v.errorf(path, "duplicates %q at %s", id, first)
+		return
+	}
+	seen[id] = path
+}
+
+// str returns the string member `key` of `obj`.
+func str(obj map[string]interface{}, key string) string {
+	s, _ := obj[key].(string)
+	return s
+}
+
+// list returns the array member `key` of `obj`.
+func list(obj map[string]interface{}, key string) []interface{} {
+	l, _ := obj[key].([]interface{})
+	return l
+}
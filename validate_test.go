@@ -0,0 +1,122 @@
+package slacker_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+	"github.com/segmentio/go-slacker/blocks"
+)
+
+// Verify `msg` fails validation with errors at `paths`.
+func testInvalid(t *testing.T, msg *slacker.Message, paths ...string) {
+	err := msg.Validate()
+	errs, ok := err.(slacker.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	var actual []string
+	for _, e := range errs {
+		actual = append(actual, e.Path)
+	}
+	assert.Equal(t, paths, actual)
+}
+
+func TestValidMessage(t *testing.T) {
+	msg := &slacker.Message{
+		Text: "Deploy api?",
+		Blocks: []interface{}{
+			blocks.Section(blocks.Markdown("Deploy *api*?")),
+			blocks.Actions(blocks.Button("deploy", "Deploy"), blocks.Button("cancel", "Cancel")),
+			blocks.Divider(),
+		},
+	}
+	assert.Equal(t, nil, msg.Validate())
+}
+
+func TestValidatesBlockCount(t *testing.T) {
+	msg := &slacker.Message{}
+	for i := 0; i <= slacker.MaxBlocks; i++ {
+		msg.Blocks = append(msg.Blocks, blocks.Divider())
+	}
+	testInvalid(t, msg, "blocks")
+}
+
+func TestValidatesTextLength(t *testing.T) {
+	msg := &slacker.Message{Blocks: []interface{}{
+		blocks.Divider(),
+		blocks.Section(blocks.Markdown(strings.Repeat("a", slacker.MaxSectionText+1))),
+		blocks.Header(strings.Repeat("a", slacker.MaxHeaderText+1)),
+	}}
+	testInvalid(t, msg, "blocks[1].text.text", "blocks[2].text.text")
+}
+
+func TestValidatesDuplicateIDs(t *testing.T) {
+	first := blocks.Section(blocks.PlainText("first"))
+	first.BlockID = "deploy"
+	second := blocks.Section(blocks.PlainText("second"))
+	second.BlockID = "deploy"
+
+	msg := &slacker.Message{Blocks: []interface{}{
+		first,
+		second,
+		blocks.Actions(blocks.Button("go", "Go"), blocks.Button("go", "Go")),
+	}}
+	testInvalid(t, msg, "blocks[1].block_id", "blocks[2].elements[1].action_id")
+
+	err := msg.Validate().(slacker.ValidationErrors)
+	assert.Equal(t, `duplicates "go" at blocks[2].elements[0].action_id`, err[1].Message)
+}
+
+func TestAllowsActionIDsInSeparateBlocks(t *testing.T) {
+	api := blocks.Actions(blocks.Button("approve", "Approve"))
+	api.BlockID = "api"
+	web := blocks.Actions(blocks.Button("approve", "Approve"))
+	web.BlockID = "web"
+
+	msg := &slacker.Message{Blocks: []interface{}{api, web}}
+	assert.Equal(t, nil, msg.Validate())
+}
+
+func TestValidatesElements(t *testing.T) {
+	var buttons []blocks.Element
+	for i := 0; i <= slacker.MaxActionsElements; i++ {
+		buttons = append(buttons, blocks.Button("", "Go"))
+	}
+
+	msg := &slacker.Message{Blocks: []interface{}{
+		blocks.Actions(buttons...),
+		blocks.Actions(blocks.Overflow("more", blocks.NewOption("Logs", "logs"))),
+		map[string]interface{}{"text": "untyped"},
+	}}
+	testInvalid(t, msg, "blocks[0].elements", "blocks[1].elements[0].options", "blocks[2].type")
+}
+
+func TestRejectsInvalidReply(t *testing.T) {
+	incidents := make(chan *slacker.Incident, 1)
+	slack := slacker.New()
+	slack.Reporter = slacker.ReporterFunc(func(ctx context.Context, incident *slacker.Incident) {
+		incidents <- incident
+	})
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		slacker.Response(w).AddBlocks(blocks.Actions())
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "foo")
+
+	// The invalid reply is reported, and replaced with an apology.
+	msg := postMessage(t, ts.URL, values)
+	incident := <-incidents
+	assert.Equal(t, "slacker: invalid message: blocks[0].elements: is empty", incident.Err.Error())
+	assert.T(t, strings.HasSuffix(msg.Text, "please report incident "+incident.ID+"."))
+}
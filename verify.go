@@ -0,0 +1,115 @@
+package slacker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// VerifyMode selects how incoming requests are authenticated.
+type VerifyMode int
+
+const (
+	// VerifyToken checks the verification token registered with each command.
+	// This is the default, but Slack has deprecated verification tokens.
+	VerifyToken VerifyMode = iota
+
+	// VerifySignature checks the X-Slack-Signature header against the signing
+	// secrets, and ignores verification tokens.
+	VerifySignature
+
+	// VerifyBoth requires both a valid token and a valid signature.
+	VerifyBoth
+)
+
+// DefaultMaxSkew is how far the X-Slack-Request-Timestamp of a signed request
+// may drift from the local clock before it is rejected as stale.
+const DefaultMaxSkew = 5 * time.Minute
+
+// maxBodySize bounds how much of a request body is read for verification.
+const maxBodySize = 1 << 20
+
+// Signature verification errors.
+var (
+	ErrMissingSignature = errors.New("slacker: missing signature")
+	ErrStaleTimestamp   = errors.New("slacker: stale request timestamp")
+	ErrInvalidSignature = errors.New("slacker: invalid signature")
+)
+
+// checksToken reports whether verification tokens are checked in mode `m`.
+func (m VerifyMode) checksToken() bool {
+	return m == VerifyToken || m == VerifyBoth
+}
+
+// checksSignature reports whether signatures are checked in mode `m`.
+func (m VerifyMode) checksSignature() bool {
+	return m == VerifySignature || m == VerifyBoth
+}
+
+// Sign returns the v0 signature of `body` sent at `timestamp` with `secret`,
+// in the format Slack sends in the X-Slack-Signature header.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	io.WriteString(mac, "v0:"+timestamp+":")
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether `signature` is a valid v0 signature of `body`
+// sent at `timestamp` with any of the given `secrets`.
+func ValidSignature(secrets []string, timestamp, signature string, body []byte) bool {
+	valid := false
+	for _, secret := range secrets {
+		expected := Sign(secret, timestamp, body)
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// verifySignature authenticates the raw body of `r` against the signing
+// secrets when the verification mode requires it. The body is read in full and
+// replaced, so it can still be parsed afterwards.
+func (s *Slacker) verifySignature(r *http.Request) error {
+	if !s.Verify.checksSignature() {
+		return nil
+	}
+
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	signature := r.Header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrStaleTimestamp
+	}
+	maxSkew := s.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	skew := time.Since(time.Unix(sec, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return ErrStaleTimestamp
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !ValidSignature(s.SigningSecrets, timestamp, signature, body) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package slacker_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	"github.com/segmentio/go-slacker"
+)
+
+// Make a signed post request to the given url with the given values.
+func postSigned(t *testing.T, url, secret string, at time.Time, values url.Values) *http.Response {
+	body := values.Encode()
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("could not create request with error: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slacker.Sign(secret, timestamp, []byte(body)))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not post request with error: %s", err)
+	}
+	return res
+}
+
+func newSignedSlacker(mode slacker.VerifyMode) *slacker.Slacker {
+	slack := slacker.New()
+	slack.Verify = mode
+	slack.SigningSecrets = []string{"old", "new"}
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		return nil
+	})
+	return slack
+}
+
+func TestSign(t *testing.T) {
+	// Example from https://api.slack.com/authentication/verifying-requests-from-slack
+	body := "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	signature := slacker.Sign("8f742231b10e8888abcd99yyyzzz85a5", "1531420618", []byte(body))
+	assert.Equal(t, "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503", signature)
+}
+
+func TestValidSignature(t *testing.T) {
+	body := []byte("command=%2Fhello")
+	signature := slacker.Sign("new", "1", body)
+
+	assert.Equal(t, true, slacker.ValidSignature([]string{"old", "new"}, "1", signature, body))
+	assert.Equal(t, false, slacker.ValidSignature([]string{"old"}, "1", signature, body))
+	assert.Equal(t, false, slacker.ValidSignature([]string{"new"}, "2", signature, body))
+	assert.Equal(t, false, slacker.ValidSignature(nil, "1", signature, body))
+}
+
+func TestAcceptsValidSignature(t *testing.T) {
+	ts := httptest.NewServer(newSignedSlacker(slacker.VerifySignature))
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+
+	// Tokens are ignored when only verifying signatures.
+	res := postSigned(t, ts.URL, "old", time.Now(), values)
+	assert.Equal(t, 200, res.StatusCode)
+}
+
+func TestRejectsInvalidSignature(t *testing.T) {
+	ts := httptest.NewServer(newSignedSlacker(slacker.VerifySignature))
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+
+	res := postSigned(t, ts.URL, "wrong", time.Now(), values)
+	assert.Equal(t, 401, res.StatusCode)
+
+	testResponse(t, ts.URL, values, 401, "Invalid signature")
+}
+
+func TestRejectsStaleTimestamp(t *testing.T) {
+	ts := httptest.NewServer(newSignedSlacker(slacker.VerifySignature))
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+
+	res := postSigned(t, ts.URL, "new", time.Now().Add(-10*time.Minute), values)
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestVerifyBoth(t *testing.T) {
+	ts := httptest.NewServer(newSignedSlacker(slacker.VerifyBoth))
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+	values.Add("token", "non-foo")
+
+	res := postSigned(t, ts.URL, "new", time.Now(), values)
+	assert.Equal(t, 401, res.StatusCode)
+
+	values.Set("token", "foo")
+	res = postSigned(t, ts.URL, "new", time.Now(), values)
+	assert.Equal(t, 200, res.StatusCode)
+}
+
+func TestIgnoresUnsignedQuery(t *testing.T) {
+	var text, responseURL string
+	slack := slacker.New()
+	slack.Verify = slacker.VerifySignature
+	slack.SigningSecrets = []string{"new"}
+	slack.HandleFunc("hello", "foo", func(w io.Writer, cmd *slacker.Command) error {
+		text, responseURL = cmd.Text, cmd.ResponseURL
+		return nil
+	})
+	ts := httptest.NewServer(slack)
+	defer ts.Close()
+
+	values := url.Values{}
+	values.Add("command", "/hello")
+
+	res := postSigned(t, ts.URL+"?text=injected&response_url=https://evil", "new", time.Now(), values)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "", text)
+	assert.Equal(t, "", responseURL)
+}